@@ -16,6 +16,7 @@ require (
 	k8s.io/apimachinery v0.33.0-beta.0
 	k8s.io/cli-runtime v0.32.3
 	k8s.io/client-go v0.32.3
+	k8s.io/metrics v0.32.3
 	sigs.k8s.io/yaml v1.4.0
 )
 
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// admissionViolation is an error the LimitRanger admission plugin would raise for a pod
+type admissionViolation struct {
+	// container is empty for violations of Pod limits
+	container string
+	message   string
+}
+
+// podContainers returns pointers to the init containers and containers of spec
+func podContainers(spec *v1.PodSpec) []*v1.Container {
+	containers := make([]*v1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	for i := range spec.InitContainers {
+		containers = append(containers, &spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		containers = append(containers, &spec.Containers[i])
+	}
+	return containers
+}
+
+// limitRangerAnnotation is the annotation the LimitRanger admission plugin records its defaults in
+const limitRangerAnnotation = "kubernetes.io/limit-ranger"
+
+// applyContainerDefaults fills in container resources the way a pod is prepared for validation:
+// the API server first defaults a missing request to the limit, then the LimitRanger admission
+// plugin sets the default limits and requests of the Container items for anything still unset.
+// It returns what the plugin set, in the format of its kubernetes.io/limit-ranger annotation.
+func applyContainerDefaults(spec *v1.PodSpec, limitRanges []*v1.LimitRange) string {
+	var annotations []string
+	for i := range spec.Containers {
+		annotations = append(annotations, defaultContainerResources(&spec.Containers[i], "container", limitRanges)...)
+	}
+	for i := range spec.InitContainers {
+		annotations = append(annotations, defaultContainerResources(&spec.InitContainers[i], "init container", limitRanges)...)
+	}
+	if len(annotations) == 0 {
+		return ""
+	}
+	return "LimitRanger plugin set: " + strings.Join(annotations, "; ")
+}
+
+// defaultContainerResources applies the defaults to one container and describes what the
+// LimitRanger admission plugin set, e.g. "cpu, memory request for container app"
+func defaultContainerResources(container *v1.Container, kind string, limitRanges []*v1.LimitRange) []string {
+	for name, limit := range container.Resources.Limits {
+		if _, ok := container.Resources.Requests[name]; !ok {
+			if container.Resources.Requests == nil {
+				container.Resources.Requests = v1.ResourceList{}
+			}
+			container.Resources.Requests[name] = limit.DeepCopy()
+		}
+	}
+
+	var setRequests, setLimits []string
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != v1.LimitTypeContainer {
+				continue
+			}
+			for name, quantity := range item.Default {
+				if _, ok := container.Resources.Limits[name]; !ok {
+					if container.Resources.Limits == nil {
+						container.Resources.Limits = v1.ResourceList{}
+					}
+					container.Resources.Limits[name] = quantity.DeepCopy()
+					setLimits = append(setLimits, string(name))
+				}
+			}
+			for name, quantity := range item.DefaultRequest {
+				if _, ok := container.Resources.Requests[name]; !ok {
+					if container.Resources.Requests == nil {
+						container.Resources.Requests = v1.ResourceList{}
+					}
+					container.Resources.Requests[name] = quantity.DeepCopy()
+					setRequests = append(setRequests, string(name))
+				}
+			}
+		}
+	}
+
+	var annotations []string
+	if len(setRequests) > 0 {
+		sort.Strings(setRequests)
+		annotations = append(annotations, fmt.Sprintf("%s request for %s %s", strings.Join(setRequests, ", "), kind, container.Name))
+	}
+	if len(setLimits) > 0 {
+		sort.Strings(setLimits)
+		annotations = append(annotations, fmt.Sprintf("%s limit for %s %s", strings.Join(setLimits, ", "), kind, container.Name))
+	}
+	return annotations
+}
+
+// validatePodResources checks the resources of spec against the Container and Pod items of
+// limitRanges with the rules and messages of the LimitRanger admission plugin
+func validatePodResources(spec *v1.PodSpec, limitRanges []*v1.LimitRange) []admissionViolation {
+	var violations []admissionViolation
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			switch item.Type {
+			case v1.LimitTypeContainer:
+				for _, container := range podContainers(spec) {
+					for _, message := range limitConstraints(item, container.Resources.Requests, container.Resources.Limits) {
+						violations = append(violations, admissionViolation{container: container.Name, message: message})
+					}
+				}
+			case v1.LimitTypePod:
+				requests, limits := podResources(spec)
+				for _, message := range limitConstraints(item, requests, limits) {
+					violations = append(violations, admissionViolation{message: message})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// limitConstraints returns the min, max and ratio constraints of item that requests and limits break
+func limitConstraints(item v1.LimitRangeItem, requests, limits v1.ResourceList) []string {
+	var messages []string
+	for _, name := range itemResourceNames(item) {
+		request, hasRequest := requests[name]
+		limit, hasLimit := limits[name]
+
+		if minimum, ok := item.Min[name]; ok {
+			switch {
+			case !hasRequest:
+				messages = append(messages, fmt.Sprintf("minimum %s usage per %s is %s.  No request is specified", name, item.Type, minimum.String()))
+			case request.Cmp(minimum) < 0:
+				messages = append(messages, fmt.Sprintf("minimum %s usage per %s is %s, but request is %s", name, item.Type, minimum.String(), request.String()))
+			case hasLimit && limit.Cmp(minimum) < 0:
+				messages = append(messages, fmt.Sprintf("minimum %s usage per %s is %s, but limit is %s", name, item.Type, minimum.String(), limit.String()))
+			}
+		}
+
+		if maximum, ok := item.Max[name]; ok {
+			switch {
+			case !hasLimit:
+				messages = append(messages, fmt.Sprintf("maximum %s usage per %s is %s.  No limit is specified", name, item.Type, maximum.String()))
+			case limit.Cmp(maximum) > 0:
+				messages = append(messages, fmt.Sprintf("maximum %s usage per %s is %s, but limit is %s", name, item.Type, maximum.String(), limit.String()))
+			case hasRequest && request.Cmp(maximum) > 0:
+				messages = append(messages, fmt.Sprintf("maximum %s usage per %s is %s, but request is %s", name, item.Type, maximum.String(), request.String()))
+			}
+		}
+
+		if ratio, ok := item.MaxLimitRequestRatio[name]; ok {
+			switch {
+			case !hasRequest || request.IsZero():
+				messages = append(messages, fmt.Sprintf("%s max limit to request ratio per %s is %s, but no request is specified or request is 0", name, item.Type, ratio.String()))
+			case !hasLimit || limit.IsZero():
+				messages = append(messages, fmt.Sprintf("%s max limit to request ratio per %s is %s, but no limit is specified or limit is 0", name, item.Type, ratio.String()))
+			default:
+				observed := limit.AsApproximateFloat64() / request.AsApproximateFloat64()
+				if observed > ratio.AsApproximateFloat64() {
+					messages = append(messages, fmt.Sprintf("%s max limit to request ratio per %s is %s, but provided ratio is %f", name, item.Type, ratio.String(), observed))
+				}
+			}
+		}
+	}
+	return messages
+}
+
+// podResources returns the requests and limits of spec as a whole the way LimitRanger sums them:
+// containers and sidecars (init containers that restart always) add up, an init container
+// raises the total when it outweighs it together with the sidecars started before it, and the
+// pod overhead comes on top
+func podResources(spec *v1.PodSpec) (requests, limits v1.ResourceList) {
+	requests = podResourceList(spec, func(r v1.ResourceRequirements) v1.ResourceList { return r.Requests })
+	limits = podResourceList(spec, func(r v1.ResourceRequirements) v1.ResourceList { return r.Limits })
+	return requests, limits
+}
+
+// podResourceList sums the list selected by listOf over the containers of spec, see podResources
+func podResourceList(spec *v1.PodSpec, listOf func(v1.ResourceRequirements) v1.ResourceList) v1.ResourceList {
+	total := v1.ResourceList{}
+	for _, container := range spec.Containers {
+		addResourceList(total, listOf(container.Resources))
+	}
+	sidecars, initPeak := v1.ResourceList{}, v1.ResourceList{}
+	for _, container := range spec.InitContainers {
+		running := v1.ResourceList{}
+		if container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			// Sidecars keep running next to the containers
+			addResourceList(total, listOf(container.Resources))
+			addResourceList(sidecars, listOf(container.Resources))
+			addResourceList(running, sidecars)
+		} else {
+			addResourceList(running, listOf(container.Resources))
+			addResourceList(running, sidecars)
+		}
+		maxResourceList(initPeak, running)
+	}
+	maxResourceList(total, initPeak)
+	addResourceList(total, spec.Overhead)
+	return total
+}
+
+// addResourceList adds every quantity of add to list
+func addResourceList(list, add v1.ResourceList) {
+	for name, quantity := range add {
+		sum := list[name].DeepCopy()
+		sum.Add(quantity)
+		list[name] = sum
+	}
+}
+
+// maxResourceList raises every quantity of list to the one in other when that is larger
+func maxResourceList(list, other v1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := list[name]; !ok || quantity.Cmp(current) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
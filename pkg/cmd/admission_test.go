@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func resources(values ...string) v1.ResourceList {
+	list := v1.ResourceList{}
+	for i := 0; i < len(values); i += 2 {
+		list[v1.ResourceName(values[i])] = resource.MustParse(values[i+1])
+	}
+	return list
+}
+
+func TestLimitConstraints(t *testing.T) {
+	item := v1.LimitRangeItem{
+		Type:                 v1.LimitTypeContainer,
+		Min:                  resources("cpu", "100m"),
+		Max:                  resources("cpu", "1", "memory", "1Gi"),
+		MaxLimitRequestRatio: resources("cpu", "2"),
+	}
+
+	tests := []struct {
+		name     string
+		requests v1.ResourceList
+		limits   v1.ResourceList
+		expected []string
+	}{
+		{
+			name:     "within bounds",
+			requests: resources("cpu", "500m", "memory", "256Mi"),
+			limits:   resources("cpu", "1000m", "memory", "1Gi"),
+		},
+		{
+			name: "nothing specified",
+			expected: []string{
+				"minimum cpu usage per Container is 100m.  No request is specified",
+				"maximum cpu usage per Container is 1.  No limit is specified",
+				"cpu max limit to request ratio per Container is 2, but no request is specified or request is 0",
+				"maximum memory usage per Container is 1Gi.  No limit is specified",
+			},
+		},
+		{
+			name:     "outside bounds",
+			requests: resources("cpu", "50m", "memory", "2Gi"),
+			limits:   resources("cpu", "2", "memory", "2Gi"),
+			expected: []string{
+				"minimum cpu usage per Container is 100m, but request is 50m",
+				"maximum cpu usage per Container is 1, but limit is 2",
+				"cpu max limit to request ratio per Container is 2, but provided ratio is 40.000000",
+				"maximum memory usage per Container is 1Gi, but limit is 2Gi",
+			},
+		},
+		{
+			name:     "request above max",
+			requests: resources("cpu", "200m", "memory", "2Gi"),
+			limits:   resources("cpu", "200m", "memory", "1Gi"),
+			expected: []string{
+				"maximum memory usage per Container is 1Gi, but request is 2Gi",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, limitConstraints(item, tt.requests, tt.limits))
+		})
+	}
+}
+
+func TestApplyContainerDefaults(t *testing.T) {
+	limitRanges := []*v1.LimitRange{{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+		Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Default:        resources("cpu", "500m", "memory", "512Mi"),
+				DefaultRequest: resources("cpu", "100m", "memory", "128Mi"),
+			},
+			{
+				Type: v1.LimitTypePod,
+				Max:  resources("cpu", "4"),
+			},
+		}},
+	}}
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "init"}},
+		Containers: []v1.Container{
+			{Name: "limited", Resources: v1.ResourceRequirements{Limits: resources("cpu", "2")}},
+			{Name: "requested", Resources: v1.ResourceRequirements{Requests: resources("memory", "1Gi")}},
+		},
+	}
+
+	assert.Equal(t, "LimitRanger plugin set: memory request for container limited; memory limit for container limited; "+
+		"cpu request for container requested; cpu, memory limit for container requested; "+
+		"cpu, memory request for init container init; cpu, memory limit for init container init",
+		applyContainerDefaults(spec, limitRanges))
+
+	assert.Equal(t, v1.ResourceRequirements{
+		Requests: resources("cpu", "100m", "memory", "128Mi"),
+		Limits:   resources("cpu", "500m", "memory", "512Mi"),
+	}, spec.InitContainers[0].Resources)
+	assert.Equal(t, v1.ResourceRequirements{
+		Requests: resources("cpu", "2", "memory", "128Mi"),
+		Limits:   resources("cpu", "2", "memory", "512Mi"),
+	}, spec.Containers[0].Resources, "the API server defaults a missing request to the limit first")
+	assert.Equal(t, v1.ResourceRequirements{
+		Requests: resources("cpu", "100m", "memory", "1Gi"),
+		Limits:   resources("cpu", "500m", "memory", "512Mi"),
+	}, spec.Containers[1].Resources)
+}
+
+func TestValidatePodResources(t *testing.T) {
+	limitRanges := []*v1.LimitRange{{
+		Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{
+			{Type: v1.LimitTypeContainer, Max: resources("cpu", "2")},
+			{Type: v1.LimitTypePod, Max: resources("cpu", "3")},
+		}},
+	}}
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "init", Resources: v1.ResourceRequirements{Limits: resources("cpu", "2500m")}}},
+		Containers: []v1.Container{
+			{Name: "a", Resources: v1.ResourceRequirements{Limits: resources("cpu", "2")}},
+			{Name: "b", Resources: v1.ResourceRequirements{Limits: resources("cpu", "1500m")}},
+		},
+	}
+
+	assert.Equal(t, []admissionViolation{
+		{container: "init", message: "maximum cpu usage per Container is 2, but limit is 2500m"},
+		{message: "maximum cpu usage per Pod is 3, but limit is 3500m"},
+	}, validatePodResources(spec, limitRanges))
+}
+
+func TestPodResources(t *testing.T) {
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Resources: v1.ResourceRequirements{Requests: resources("cpu", "3"), Limits: resources("memory", "1Gi")}},
+		},
+		Containers: []v1.Container{
+			{Resources: v1.ResourceRequirements{Requests: resources("cpu", "1", "memory", "256Mi"), Limits: resources("memory", "512Mi")}},
+			{Resources: v1.ResourceRequirements{Requests: resources("cpu", "500m"), Limits: resources("memory", "256Mi")}},
+		},
+		Overhead: resources("cpu", "100m"),
+	}
+
+	requests, limits := podResources(spec)
+	cpu, memory := requests[v1.ResourceCPU], requests[v1.ResourceMemory]
+	assert.Equal(t, "3100m", cpu.String(), "the largest init container outweighs the sum of the containers")
+	assert.Equal(t, "256Mi", memory.String())
+	limitCPU, limitMemory := limits[v1.ResourceCPU], limits[v1.ResourceMemory]
+	assert.Equal(t, "100m", limitCPU.String())
+	assert.Equal(t, "1Gi", limitMemory.String())
+}
+
+func TestPodResourcesWithSidecars(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Name: "setup", Resources: v1.ResourceRequirements{Requests: resources("cpu", "1")}},
+			{Name: "proxy", RestartPolicy: &always, Resources: v1.ResourceRequirements{Requests: resources("cpu", "500m"), Limits: resources("memory", "128Mi")}},
+			{Name: "migrate", Resources: v1.ResourceRequirements{Requests: resources("cpu", "2")}},
+		},
+		Containers: []v1.Container{
+			{Name: "app", Resources: v1.ResourceRequirements{Requests: resources("cpu", "1"), Limits: resources("memory", "256Mi")}},
+		},
+	}
+
+	requests, limits := podResources(spec)
+	cpu := requests[v1.ResourceCPU]
+	assert.Equal(t, "2500m", cpu.String(), "migrate runs next to the proxy started before it")
+	memory := limits[v1.ResourceMemory]
+	assert.Equal(t, "384Mi", memory.String(), "the proxy adds up with the app")
+
+	spec.InitContainers[2].Resources.Requests = resources("cpu", "500m")
+	requests, _ = podResources(spec)
+	cpu = requests[v1.ResourceCPU]
+	assert.Equal(t, "1500m", cpu.String(), "the app and the proxy outweigh every init container")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultFieldManager is the field manager recorded by server-side apply unless --field-manager is set
+const defaultFieldManager = "kubectl-create-limitrange"
+
+// applyLimitRange sends a single LimitRange with server-side apply and prints the result
+func (o *LimitOptions) applyLimitRange(clientset kubernetes.Interface, limitRange *v1.LimitRange) error {
+	// The apply configuration must name its type, and must not carry server-owned fields
+	applyConfiguration := limitRange.DeepCopy()
+	applyConfiguration.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRange"}
+	applyConfiguration.ResourceVersion = ""
+	applyConfiguration.ManagedFields = nil
+
+	serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{})
+	data, err := runtime.Encode(serializer, applyConfiguration)
+	if err != nil {
+		return fmt.Errorf("failed to encode LimitRange for server-side apply: %w", err)
+	}
+
+	force := o.forceConflicts
+	patchOptions := metav1.PatchOptions{
+		FieldManager: o.fieldManager,
+		Force:        &force,
+	}
+	if o.dryRun == "server" {
+		patchOptions.DryRun = []string{"All"}
+	}
+
+	appliedLimitRange, err := clientset.CoreV1().LimitRanges(limitRange.Namespace).Patch(context.TODO(), limitRange.Name, types.ApplyPatchType, data, patchOptions)
+	if err != nil {
+		return fmt.Errorf("failed to apply LimitRange: %w", err)
+	}
+
+	// Print the server response for server-side dry-run, or when an output format is requested
+	if o.dryRun == "server" || o.output != "" {
+		if appliedLimitRange == nil {
+			appliedLimitRange = limitRange
+		}
+		return o.printOutputWithTypeMeta(appliedLimitRange, "serverside-applied")
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "limitrange.core %q serverside-applied\n", limitRange.Name)
+	return nil
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newServerSideOptions(fakeClientset kubernetes.Interface) *LimitOptions {
+	options := &LimitOptions{
+		name:         "test-limitrange",
+		namespace:    "default",
+		maxCPU:       "1",
+		serverSide:   true,
+		fieldManager: defaultFieldManager,
+		IOStreams:    genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func TestRunServerSideApplyIsIdempotent(t *testing.T) {
+	fakeClientset := fake.NewClientset()
+	options := newServerSideOptions(fakeClientset)
+
+	assert.NoError(t, options.Run(), "expected first apply to create the LimitRange")
+	options.maxCPU = "2"
+	assert.NoError(t, options.Run(), "expected second apply to update the LimitRange")
+
+	lr, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	if assert.NoError(t, err) && assert.Len(t, lr.Spec.Limits, 1) {
+		maxCPU := lr.Spec.Limits[0].Max[v1.ResourceCPU]
+		assert.Equal(t, "2", (&maxCPU).String())
+	}
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "limitrange.core \"test-limitrange\" serverside-applied")
+}
+
+func TestRunServerSideApplyOptions(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	options := newServerSideOptions(fakeClientset)
+	options.fieldManager = "platform-pipeline"
+	options.forceConflicts = true
+	options.dryRun = "server"
+	options.output = "yaml"
+
+	var patchAction k8stesting.PatchAction
+	fakeClientset.Fake.PrependReactor("patch", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction = action.(k8stesting.PatchAction)
+		return true, &v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "test-limitrange", Namespace: "default"}}, nil
+	})
+
+	err := options.Run()
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, patchAction) {
+		assert.Equal(t, types.ApplyPatchType, patchAction.GetPatchType())
+		assert.Equal(t, "test-limitrange", patchAction.GetName())
+		assert.Contains(t, string(patchAction.GetPatch()), `"kind":"LimitRange"`)
+		assert.Contains(t, string(patchAction.GetPatch()), `"cpu":"1"`)
+
+		patchOptions := patchAction.(k8stesting.PatchActionImpl).PatchOptions
+		assert.Equal(t, "platform-pipeline", patchOptions.FieldManager)
+		if assert.NotNil(t, patchOptions.Force) {
+			assert.True(t, *patchOptions.Force)
+		}
+		assert.Equal(t, []string{"All"}, patchOptions.DryRun)
+	}
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "kind: LimitRange")
+	assert.Contains(t, output, "name: test-limitrange")
+}
+
+func TestValidateServerSideOptions(t *testing.T) {
+	options := &LimitOptions{
+		name:           "test-limitrange",
+		namespace:      "default",
+		maxCPU:         "1",
+		forceConflicts: true,
+	}
+	err := options.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--force-conflicts can only be used with --server-side")
+	}
+
+	options.serverSide = true
+	err = options.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--field-manager cannot be empty with --server-side")
+	}
+
+	options.fieldManager = "platform-pipeline"
+	assert.NoError(t, options.Validate())
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	auditExample = `
+    # List the containers of a namespace that a stricter CPU policy would reject
+    kubectl lr audit --namespace=my-namespace --max-cpu=1 --min-cpu=100m --ratio-cpu=4
+
+    # Audit the workloads against the LimitRanges of a spec file and print JSON
+    kubectl lr audit -f limits.yaml -o json
+    `
+)
+
+// auditedKinds are the kinds of workloads an audit checks, in the order they are reported
+var auditedKinds = map[string]int{"Pod": 0, "Deployment": 1, "StatefulSet": 2, "Job": 3}
+
+// auditedWorkload is a workload along with the pod spec it creates pods from
+type auditedWorkload struct {
+	kind string
+	name string
+	spec v1.PodSpec
+}
+
+// auditFinding is a violation of the proposed limits by one workload
+type auditFinding struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	Violation string `json:"violation"`
+}
+
+// auditReport is the result of an audit as printed with -o json
+type auditReport struct {
+	Checked    int            `json:"checked"`
+	Violating  int            `json:"violating"`
+	Violations []auditFinding `json:"violations"`
+}
+
+// AuditOptions holds information required to audit workloads against a proposed LimitRange
+type AuditOptions struct {
+	*LimitOptions
+}
+
+// NewCmdAudit creates a cobra command listing the workloads a proposed LimitRange would reject
+func NewCmdAudit(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &AuditOptions{LimitOptions: NewLimitOptions(streams)}
+
+	cmd := &cobra.Command{
+		Use:   "audit [NAME] [flags]",
+		Short: "Report the workloads that would violate a proposed LimitRange",
+		Long: "Check the Pods, Deployments, StatefulSets and Jobs of a namespace against the LimitRange built from " +
+			"the same flags, preset or spec file as create. Containers get the proposed defaults first, as they would " +
+			"when their pods are next created, and every request or limit outside the min, max or ratio is reported.",
+		Example:      auditExample,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			o.nameProposedLimitRange(args)
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Validate(); err != nil {
+				return fmt.Errorf("validation error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.addLimitFlags(cmd)
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format. One of: json. Defaults to a table")
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// nameProposedLimitRange takes the name of the proposed LimitRange from args. The name does not
+// change how pods are admitted, so it defaults to "proposed" when no spec file names it either.
+func (o *LimitOptions) nameProposedLimitRange(args []string) {
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	if o.name == "" && o.filename == "" {
+		o.name = "proposed"
+	}
+}
+
+// Validate checks the proposed LimitRange and the output format
+func (o *AuditOptions) Validate() error {
+	if o.output != "" && o.output != "json" {
+		return fmt.Errorf("unsupported output format: %s", o.output)
+	}
+	return o.LimitOptions.Validate()
+}
+
+// Run checks the workloads of every namespace with a proposed LimitRange and prints the violations
+func (o *AuditOptions) Run() error {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	// Every LimitRange of a namespace applies to its pods, so audit against all of them at once
+	proposed := map[string][]*v1.LimitRange{}
+	var namespaces []string
+	for _, limitRange := range o.storedLimitRanges() {
+		if _, ok := proposed[limitRange.Namespace]; !ok {
+			namespaces = append(namespaces, limitRange.Namespace)
+		}
+		proposed[limitRange.Namespace] = append(proposed[limitRange.Namespace], limitRange)
+	}
+	sort.Strings(namespaces)
+
+	report := auditReport{Violations: []auditFinding{}}
+	for _, namespace := range namespaces {
+		workloads, err := listWorkloads(clientset, namespace)
+		if err != nil {
+			return err
+		}
+		for _, workload := range workloads {
+			report.Checked++
+			spec := workload.spec.DeepCopy()
+			applyContainerDefaults(spec, proposed[namespace])
+			violations := validatePodResources(spec, proposed[namespace])
+			if len(violations) > 0 {
+				report.Violating++
+			}
+			for _, violation := range violations {
+				report.Violations = append(report.Violations, auditFinding{
+					Namespace: namespace,
+					Kind:      workload.kind,
+					Name:      workload.name,
+					Container: violation.container,
+					Violation: violation.message,
+				})
+			}
+		}
+	}
+
+	if o.output == "json" {
+		output, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintf(o.IOStreams.Out, "%s\n", output)
+		return nil
+	}
+	return printAuditTable(o.IOStreams.Out, report)
+}
+
+// printAuditTable prints one row per violation followed by a summary line
+func printAuditTable(out io.Writer, report auditReport) error {
+	if report.Violating == 0 {
+		fmt.Fprintf(out, "All %d workloads comply with the proposed limits.\n", report.Checked)
+		return nil
+	}
+
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "NAMESPACE\tKIND\tNAME\tCONTAINER\tVIOLATION")
+	for _, finding := range report.Violations {
+		container := finding.Container
+		if container == "" {
+			container = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", finding.Namespace, finding.Kind, finding.Name, container, finding.Violation)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%d of %d workloads would violate the proposed limits.\n", report.Violating, report.Checked)
+	return nil
+}
+
+// listWorkloads returns the active Pods and the Deployments, StatefulSets and Jobs of namespace
+func listWorkloads(clientset kubernetes.Interface, namespace string) ([]auditedWorkload, error) {
+	var workloads []auditedWorkload
+	add := func(kind, name string, spec v1.PodSpec) {
+		workloads = append(workloads, auditedWorkload{kind: kind, name: name, spec: spec})
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		// Finished pods never run again
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		add("Pod", pod.Name, pod.Spec)
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		add("Deployment", deployment.Name, deployment.Spec.Template.Spec)
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		add("StatefulSet", statefulSet.Name, statefulSet.Spec.Template.Spec)
+	}
+
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, job := range jobs.Items {
+		add("Job", job.Name, job.Spec.Template.Spec)
+	}
+
+	sort.SliceStable(workloads, func(i, j int) bool {
+		if workloads[i].kind != workloads[j].kind {
+			return auditedKinds[workloads[i].kind] < auditedKinds[workloads[j].kind]
+		}
+		return workloads[i].name < workloads[j].name
+	})
+	return workloads, nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newAuditOptions(fakeClientset kubernetes.Interface) *AuditOptions {
+	options := &LimitOptions{
+		name:      "proposed",
+		namespace: "default",
+		maxCPU:    "1",
+		minCPU:    "100m",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return &AuditOptions{LimitOptions: options}
+}
+
+func podTemplate(containers ...v1.Container) v1.PodTemplateSpec {
+	return v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: containers}}
+}
+
+func auditedWorkloads() *fake.Clientset {
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default"}
+	}
+	return fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: meta("big-pod"),
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Limits: resources("cpu", "2")}}}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		},
+		&v1.Pod{
+			ObjectMeta: meta("finished-pod"),
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Resources: v1.ResourceRequirements{Limits: resources("cpu", "2")}}}},
+			Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: meta("web"),
+			Spec:       appsv1.DeploymentSpec{Template: podTemplate(v1.Container{Name: "nginx"})},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: meta("db"),
+			Spec: appsv1.StatefulSetSpec{Template: podTemplate(
+				v1.Container{Name: "postgres", Resources: v1.ResourceRequirements{Requests: resources("cpu", "50m"), Limits: resources("cpu", "500m")}},
+			)},
+		},
+		&batchv1.Job{
+			ObjectMeta: meta("migrate"),
+			Spec:       batchv1.JobSpec{Template: podTemplate(v1.Container{Name: "migrate", Resources: v1.ResourceRequirements{Limits: resources("cpu", "1")}})},
+		},
+	)
+}
+
+func TestRunAuditTable(t *testing.T) {
+	options := newAuditOptions(auditedWorkloads())
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t,
+		"NAMESPACE   KIND          NAME      CONTAINER   VIOLATION\n"+
+			"default     Pod           big-pod   app         maximum cpu usage per Container is 1, but limit is 2\n"+
+			"default     StatefulSet   db        postgres    minimum cpu usage per Container is 100m, but request is 50m\n"+
+			"\n"+
+			"2 of 4 workloads would violate the proposed limits.\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunAuditJSON(t *testing.T) {
+	options := newAuditOptions(auditedWorkloads())
+	options.maxCPU = "4"
+	options.minCPU = ""
+	options.ratioCPU = "2"
+	options.output = "json"
+
+	assert.NoError(t, options.Run())
+
+	var report auditReport
+	assert.NoError(t, json.Unmarshal(options.IOStreams.Out.(*bytes.Buffer).Bytes(), &report))
+	assert.Equal(t, auditReport{
+		Checked:   4,
+		Violating: 1,
+		Violations: []auditFinding{{
+			Namespace: "default",
+			Kind:      "StatefulSet",
+			Name:      "db",
+			Container: "postgres",
+			Violation: "cpu max limit to request ratio per Container is 2, but provided ratio is 10.000000",
+		}},
+	}, report)
+}
+
+func TestRunAuditCompliant(t *testing.T) {
+	options := newAuditOptions(fake.NewSimpleClientset())
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "All 0 workloads comply with the proposed limits.\n", options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestAuditValidate(t *testing.T) {
+	options := newAuditOptions(fake.NewSimpleClientset())
+	options.output = "yaml"
+
+	assert.EqualError(t, options.Validate(), "unsupported output format: yaml")
+
+	options.output = "json"
+	assert.NoError(t, options.Validate())
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	deleteExample = `
+    # Delete a LimitRange after confirming the prompt
+    kubectl lr delete my-limitrange --namespace=my-namespace
+
+    # Delete every LimitRange with a label, without prompting
+    kubectl lr delete -l team=payments --yes
+
+    # Show which LimitRanges would be deleted
+    kubectl lr delete --all --dry-run=client
+
+    # Delete all LimitRanges of a namespace and wait until they are gone
+    kubectl lr delete --all --namespace=my-namespace --wait --timeout=30s
+    `
+)
+
+// deletePollInterval is how often --wait checks whether a LimitRange is gone
+const deletePollInterval = 500 * time.Millisecond
+
+// DeleteOptions holds information required to delete LimitRanges
+type DeleteOptions struct {
+	configFlags   *genericclioptions.ConfigFlags
+	namespace     string
+	names         []string
+	selector      string
+	all           bool
+	yes           bool
+	dryRun        string
+	wait          bool
+	timeout       time.Duration
+	IOStreams     genericclioptions.IOStreams
+	clientsetFunc func(config *rest.Config) (kubernetes.Interface, error)
+}
+
+// NewDeleteOptions initializes an instance of DeleteOptions with default values
+func NewDeleteOptions(streams genericclioptions.IOStreams) *DeleteOptions {
+	return &DeleteOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		timeout:     time.Minute,
+		IOStreams:   streams,
+		clientsetFunc: func(config *rest.Config) (kubernetes.Interface, error) {
+			return kubernetes.NewForConfig(config)
+		},
+	}
+}
+
+// NewCmdDelete creates a cobra command deleting LimitRanges by name, label selector or all at once
+func NewCmdDelete(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewDeleteOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "delete (NAME... | -l SELECTOR | --all) [flags]",
+		Short:        "Delete LimitRanges after a confirmation prompt",
+		Example:      deleteExample,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Validate(); err != nil {
+				return fmt.Errorf("validation error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.configFlags.AddFlags(cmd.Flags())
+	if nsFlag := cmd.Flag("namespace"); nsFlag != nil {
+		nsFlag.Shorthand = "n"
+	}
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector of the LimitRanges to delete, supports '=', '==', '!=', 'in' and 'notin'")
+	cmd.Flags().BoolVar(&o.all, "all", false, "Delete every LimitRange in the namespace")
+	cmd.Flags().BoolVarP(&o.yes, "yes", "y", false, "Delete without asking for confirmation")
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", "", "Dry-run mode. One of: client|server")
+	cmd.Flags().BoolVar(&o.wait, "wait", false, "If true, wait until the LimitRanges are gone before returning")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", o.timeout, "How long --wait waits for all the LimitRanges to be deleted")
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Complete sets the names to delete and resolves the namespace
+func (o *DeleteOptions) Complete(_ *cobra.Command, args []string) error {
+	o.names = args
+	var err error
+	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to get current namespace: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that exactly one way of selecting LimitRanges is given
+func (o *DeleteOptions) Validate() error {
+	if o.namespace == "" {
+		return fmt.Errorf("namespace cannot be empty")
+	}
+	selections := 0
+	for _, given := range []bool{len(o.names) > 0, o.selector != "", o.all} {
+		if given {
+			selections++
+		}
+	}
+	if selections == 0 {
+		return fmt.Errorf("specify the LimitRanges to delete by name, with --selector or with --all")
+	}
+	if selections > 1 {
+		return fmt.Errorf("names, --selector and --all cannot be combined")
+	}
+	if o.dryRun != "" && o.dryRun != "client" && o.dryRun != "server" {
+		return fmt.Errorf("invalid value for --dry-run: %s, must be 'client' or 'server'", o.dryRun)
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("--timeout must be greater than zero")
+	}
+	return nil
+}
+
+// Run asks for confirmation, deletes the selected LimitRanges and optionally waits until they are gone
+func (o *DeleteOptions) Run() error {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	// Like kubectl delete, names that do not exist are reported once the others are deleted
+	limitRanges, missing, err := o.selectLimitRanges(clientset)
+	if err != nil {
+		return err
+	}
+	if len(limitRanges) == 0 {
+		if len(missing) > 0 {
+			return utilerrors.NewAggregate(missing)
+		}
+		fmt.Fprintf(o.IOStreams.ErrOut, "No resources found in %s namespace.\n", o.namespace)
+		return nil
+	}
+
+	// Dry runs change nothing, so there is nothing to confirm
+	if o.dryRun == "" && !o.yes {
+		confirmed, err := o.confirm(limitRanges)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(o.IOStreams.Out, "deletion is cancelled")
+			return utilerrors.NewAggregate(missing)
+		}
+	}
+
+	deleteOptions := metav1.DeleteOptions{}
+	suffix := ""
+	switch o.dryRun {
+	case "client":
+		suffix = " (dry run)"
+	case "server":
+		deleteOptions.DryRun = []string{metav1.DryRunAll}
+		suffix = " (server dry run)"
+	}
+
+	client := clientset.CoreV1().LimitRanges(o.namespace)
+	for _, limitRange := range limitRanges {
+		if o.dryRun != "client" {
+			err := client.Delete(context.TODO(), limitRange.Name, deleteOptions)
+			if apierrors.IsNotFound(err) {
+				// Deleted by someone else since it was selected
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to delete LimitRange %s: %w", limitRange.Name, err)
+			}
+		}
+		fmt.Fprintf(o.IOStreams.Out, "limitrange.core %q deleted%s\n", limitRange.Name, suffix)
+	}
+
+	if o.wait && o.dryRun == "" {
+		if err := o.waitForDeletion(clientset, limitRanges); err != nil {
+			missing = append(missing, err)
+		}
+	}
+	return utilerrors.NewAggregate(missing)
+}
+
+// selectLimitRanges returns the named LimitRanges, or the ones matching the selector or --all.
+// Each name is resolved on its own: the errors for names that do not exist are returned as
+// missing, next to the LimitRanges that were found.
+func (o *DeleteOptions) selectLimitRanges(clientset kubernetes.Interface) ([]v1.LimitRange, []error, error) {
+	client := clientset.CoreV1().LimitRanges(o.namespace)
+	if len(o.names) > 0 {
+		limitRanges := make([]v1.LimitRange, 0, len(o.names))
+		var missing []error
+		for _, name := range o.names {
+			limitRange, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				missing = append(missing, fmt.Errorf("failed to get LimitRange: %w", err))
+				continue
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get LimitRange: %w", err)
+			}
+			limitRanges = append(limitRanges, *limitRange)
+		}
+		return limitRanges, missing, nil
+	}
+
+	list, err := client.List(context.TODO(), metav1.ListOptions{LabelSelector: o.selector})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+	return list.Items, nil, nil
+}
+
+// confirm lists the LimitRanges about to be deleted and reads the answer from IOStreams.In
+func (o *DeleteOptions) confirm(limitRanges []v1.LimitRange) (bool, error) {
+	fmt.Fprintf(o.IOStreams.Out, "The following LimitRanges in namespace %s will be deleted:\n", o.namespace)
+	for _, limitRange := range limitRanges {
+		fmt.Fprintf(o.IOStreams.Out, "  %s\n", limitRange.Name)
+	}
+	fmt.Fprint(o.IOStreams.Out, "Do you want to continue? [y/N]: ")
+
+	if o.IOStreams.In == nil {
+		return false, fmt.Errorf("no input to read the confirmation from, use --yes to delete without prompting")
+	}
+	answer, err := bufio.NewReader(o.IOStreams.In).ReadString('\n')
+	if err != nil && answer == "" {
+		// End of input counts as declining
+		fmt.Fprintln(o.IOStreams.Out)
+		return false, nil
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// waitForDeletion polls until none of limitRanges exists anymore or the timeout expires.
+// The timeout covers the whole wait, not each LimitRange.
+func (o *DeleteOptions) waitForDeletion(clientset kubernetes.Interface, limitRanges []v1.LimitRange) error {
+	ctx, cancel := context.WithTimeout(context.TODO(), o.timeout)
+	defer cancel()
+
+	client := clientset.CoreV1().LimitRanges(o.namespace)
+	for _, limitRange := range limitRanges {
+		err := wait.PollUntilContextCancel(ctx, deletePollInterval, true, func(ctx context.Context) (bool, error) {
+			current, err := client.Get(ctx, limitRange.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			// A LimitRange recreated under the same name counts as deleted
+			return current.UID != limitRange.UID, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed waiting for LimitRange %s to be deleted: %w", limitRange.Name, err)
+		}
+	}
+	return nil
+}
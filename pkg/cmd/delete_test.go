@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newDeleteOptions(fakeClientset kubernetes.Interface, input string) *DeleteOptions {
+	options := &DeleteOptions{
+		namespace: "default",
+		timeout:   time.Minute,
+		IOStreams: genericclioptions.IOStreams{
+			In:     strings.NewReader(input),
+			Out:    new(bytes.Buffer),
+			ErrOut: new(bytes.Buffer),
+		},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func deletableLimitRanges() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", Labels: map[string]string{"team": "x"}}},
+		&v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default", Labels: map[string]string{"team": "y"}}},
+		&v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "other"}},
+	)
+}
+
+func remainingLimitRanges(t *testing.T, clientset kubernetes.Interface, namespace string) []string {
+	list, err := clientset.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, limitRange := range list.Items {
+		names = append(names, limitRange.Name)
+	}
+	return names
+}
+
+func TestRunDeleteConfirmed(t *testing.T) {
+	fakeClientset := deletableLimitRanges()
+	options := newDeleteOptions(fakeClientset, "y\n")
+	options.names = []string{"a"}
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t,
+		"The following LimitRanges in namespace default will be deleted:\n  a\nDo you want to continue? [y/N]: "+
+			"limitrange.core \"a\" deleted\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, []string{"b"}, remainingLimitRanges(t, fakeClientset, "default"))
+}
+
+func TestRunDeleteDeclined(t *testing.T) {
+	for _, input := range []string{"n\n", "\n", "", "whatever\n"} {
+		fakeClientset := deletableLimitRanges()
+		options := newDeleteOptions(fakeClientset, input)
+		options.all = true
+
+		assert.NoError(t, options.Run())
+		assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "deletion is cancelled\n")
+		assert.Equal(t, []string{"a", "b"}, remainingLimitRanges(t, fakeClientset, "default"))
+	}
+}
+
+func TestRunDeleteSelectorWithoutPrompt(t *testing.T) {
+	fakeClientset := deletableLimitRanges()
+	options := newDeleteOptions(fakeClientset, "")
+	options.selector = "team=y"
+	options.yes = true
+	options.wait = true
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "limitrange.core \"b\" deleted\n", options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, []string{"a"}, remainingLimitRanges(t, fakeClientset, "default"))
+	assert.Equal(t, []string{"c"}, remainingLimitRanges(t, fakeClientset, "other"))
+}
+
+func TestRunDeleteDryRun(t *testing.T) {
+	tests := []struct {
+		dryRun   string
+		expected string
+	}{
+		{"client", "limitrange.core \"a\" deleted (dry run)\nlimitrange.core \"b\" deleted (dry run)\n"},
+		{"server", "limitrange.core \"a\" deleted (server dry run)\nlimitrange.core \"b\" deleted (server dry run)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dryRun, func(t *testing.T) {
+			fakeClientset := deletableLimitRanges()
+			var deleteActions []k8stesting.DeleteAction
+			fakeClientset.PrependReactor("delete", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				deleteActions = append(deleteActions, action.(k8stesting.DeleteAction))
+				// The fake client does not implement dry run, so keep the object
+				return true, nil, nil
+			})
+			options := newDeleteOptions(fakeClientset, "")
+			options.all = true
+			options.dryRun = tt.dryRun
+
+			assert.NoError(t, options.Run())
+			assert.Equal(t, tt.expected, options.IOStreams.Out.(*bytes.Buffer).String())
+			if tt.dryRun == "server" && assert.Len(t, deleteActions, 2) {
+				assert.Equal(t, []string{metav1.DryRunAll}, deleteActions[0].GetDeleteOptions().DryRun)
+			} else {
+				assert.Empty(t, deleteActions)
+			}
+		})
+	}
+}
+
+func TestRunDeleteWaitTimeout(t *testing.T) {
+	fakeClientset := deletableLimitRanges()
+	fakeClientset.PrependReactor("delete", "limitranges", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		// Accept the deletion but keep the object, like a pending finalizer
+		return true, nil, nil
+	})
+	options := newDeleteOptions(fakeClientset, "")
+	options.names = []string{"a"}
+	options.yes = true
+	options.wait = true
+	options.timeout = 10 * time.Millisecond
+
+	err := options.Run()
+	assert.ErrorContains(t, err, "failed waiting for LimitRange a to be deleted")
+}
+
+func TestRunDeleteWaitSharesTimeout(t *testing.T) {
+	fakeClientset := deletableLimitRanges()
+	deleted := false
+	fakeClientset.PrependReactor("delete", "limitranges", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		deleted = true
+		return true, nil, nil
+	})
+	fakeClientset.PrependReactor("get", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !deleted || action.(k8stesting.GetAction).GetName() != "a" {
+			return false, nil, nil
+		}
+		// a takes most of the timeout to go away, b never does
+		time.Sleep(150 * time.Millisecond)
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "limitranges"}, "a")
+	})
+	options := newDeleteOptions(fakeClientset, "")
+	options.names = []string{"a", "b"}
+	options.yes = true
+	options.wait = true
+	options.timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	err := options.Run()
+	assert.ErrorContains(t, err, "failed waiting for LimitRange b to be deleted")
+	// Waiting for b with a timeout of its own would take 350ms
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+}
+
+func TestRunDeleteNotFound(t *testing.T) {
+	options := newDeleteOptions(deletableLimitRanges(), "")
+	options.names = []string{"missing"}
+
+	assert.EqualError(t, options.Run(), `failed to get LimitRange: limitranges "missing" not found`)
+
+	options = newDeleteOptions(deletableLimitRanges(), "")
+	options.selector = "team=z"
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "No resources found in default namespace.\n", options.IOStreams.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestRunDeletePartlyMissing(t *testing.T) {
+	fakeClientset := deletableLimitRanges()
+	options := newDeleteOptions(fakeClientset, "")
+	options.names = []string{"a", "missing"}
+	options.yes = true
+
+	assert.EqualError(t, options.Run(), `failed to get LimitRange: limitranges "missing" not found`)
+	assert.Equal(t, "limitrange.core \"a\" deleted\n", options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, []string{"b"}, remainingLimitRanges(t, fakeClientset, "default"))
+}
+
+func TestDeleteValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     DeleteOptions
+		expectedErr string
+	}{
+		{
+			name:    "valid",
+			options: DeleteOptions{namespace: "default", names: []string{"a"}, dryRun: "server", timeout: time.Second},
+		},
+		{
+			name:        "nothing selected",
+			options:     DeleteOptions{namespace: "default", timeout: time.Second},
+			expectedErr: "specify the LimitRanges to delete by name, with --selector or with --all",
+		},
+		{
+			name:        "name and all",
+			options:     DeleteOptions{namespace: "default", names: []string{"a"}, all: true, timeout: time.Second},
+			expectedErr: "names, --selector and --all cannot be combined",
+		},
+		{
+			name:        "invalid dry run",
+			options:     DeleteOptions{namespace: "default", all: true, dryRun: "none", timeout: time.Second},
+			expectedErr: "invalid value for --dry-run: none, must be 'client' or 'server'",
+		},
+		{
+			name:        "invalid timeout",
+			options:     DeleteOptions{namespace: "default", all: true},
+			expectedErr: "--timeout must be greater than zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	describeExample = `
+    # Show the limits that apply to new containers and pods in a namespace
+    kubectl lr describe --namespace=my-namespace
+    `
+)
+
+// policyFields are the LimitRangeItem fields in the column order of limitTableHeader
+var policyFields = []limitField{limitFieldMin, limitFieldMax, limitFieldDefaultRequest, limitFieldDefault, limitFieldRatio}
+
+// policyValue is a quantity along with the name of the LimitRange that sets it
+type policyValue struct {
+	quantity resource.Quantity
+	source   string
+}
+
+// String formats the value as "QUANTITY (SOURCE)"
+func (v policyValue) String() string {
+	return fmt.Sprintf("%s (%s)", v.quantity.String(), v.source)
+}
+
+// effectiveLimit collects every value that the LimitRanges of a namespace set for one
+// resource of one item type
+type effectiveLimit struct {
+	limitType v1.LimitType
+	resource  v1.ResourceName
+	values    map[limitField][]policyValue
+}
+
+// effective returns the value of field that the LimitRanger admission plugin enforces.
+// Every min, max and ratio is enforced, so the most restrictive one wins. Defaults are only
+// filled in when unset, so the value returned for them only applies when they are not ambiguous.
+func (l effectiveLimit) effective(field limitField) (policyValue, bool) {
+	values := l.values[field]
+	if len(values) == 0 {
+		return policyValue{}, false
+	}
+	result := values[0]
+	for _, value := range values[1:] {
+		switch field {
+		case limitFieldMin:
+			if value.quantity.Cmp(result.quantity) > 0 {
+				result = value
+			}
+		case limitFieldMax, limitFieldRatio:
+			if value.quantity.Cmp(result.quantity) < 0 {
+				result = value
+			}
+		}
+	}
+	return result, true
+}
+
+// ambiguous reports whether LimitRanges set field to different defaults. Which of them is filled
+// in depends on the order the admission plugin applies the LimitRanges in, so none can be
+// shown as the one that applies.
+func (l effectiveLimit) ambiguous(field limitField) bool {
+	return (field == limitFieldDefault || field == limitFieldDefaultRequest) && hasDistinctValues(l.values[field])
+}
+
+// DescribeOptions holds information required to describe the effective limits of a namespace
+type DescribeOptions struct {
+	configFlags   *genericclioptions.ConfigFlags
+	namespace     string
+	IOStreams     genericclioptions.IOStreams
+	clientsetFunc func(config *rest.Config) (kubernetes.Interface, error)
+}
+
+// NewDescribeOptions initializes an instance of DescribeOptions with default values
+func NewDescribeOptions(streams genericclioptions.IOStreams) *DescribeOptions {
+	return &DescribeOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+		clientsetFunc: func(config *rest.Config) (kubernetes.Interface, error) {
+			return kubernetes.NewForConfig(config)
+		},
+	}
+}
+
+// NewCmdDescribe creates a cobra command explaining the limits that apply in a namespace
+func NewCmdDescribe(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewDescribeOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "describe [flags]",
+		Short: "Explain the effective limits of all LimitRanges in a namespace",
+		Long: "Merge every LimitRange of a namespace into the policy that applies to new containers, pods and " +
+			"PersistentVolumeClaims, showing which LimitRange each value comes from and flagging conflicts between them.",
+		Example:      describeExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.configFlags.AddFlags(cmd.Flags())
+	if nsFlag := cmd.Flag("namespace"); nsFlag != nil {
+		nsFlag.Shorthand = "n"
+	}
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Complete resolves the namespace
+func (o *DescribeOptions) Complete(_ *cobra.Command, _ []string) error {
+	var err error
+	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to get current namespace: %w", err)
+	}
+	return nil
+}
+
+// Run lists the LimitRanges of the namespace and prints their effective policy and conflicts
+func (o *DescribeOptions) Run() error {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	list, err := clientset.CoreV1().LimitRanges(o.namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+	if len(list.Items) == 0 {
+		fmt.Fprintf(o.IOStreams.Out, "No LimitRanges in namespace %s, containers are admitted without limits.\n", o.namespace)
+		return nil
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+
+	names := make([]string, 0, len(list.Items))
+	for _, limitRange := range list.Items {
+		names = append(names, limitRange.Name)
+	}
+	limits := effectivePolicy(list.Items)
+
+	fmt.Fprintf(o.IOStreams.Out, "Namespace:    %s\nLimitRanges:  %s\n\nEffective limits:\n", o.namespace, strings.Join(names, ", "))
+	w := printers.GetNewTabWriter(o.IOStreams.Out)
+	fmt.Fprintf(w, "%s\n", strings.Join(limitTableHeader, "\t"))
+	for _, limit := range limits {
+		row := []string{string(limit.limitType), string(limit.resource)}
+		for _, field := range policyFields {
+			cell := "-"
+			if limit.ambiguous(field) {
+				// The conflicts below list the candidates
+				cell = "<ambiguous>"
+			} else if value, ok := limit.effective(field); ok {
+				cell = value.String()
+			}
+			row = append(row, cell)
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(row, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	conflicts := policyConflicts(limits)
+	if len(conflicts) == 0 {
+		fmt.Fprintln(o.IOStreams.Out, "\nNo conflicts.")
+		return nil
+	}
+	fmt.Fprintln(o.IOStreams.Out, "\nConflicts:")
+	for _, conflict := range conflicts {
+		fmt.Fprintf(o.IOStreams.Out, "  - %s\n", conflict)
+	}
+	return nil
+}
+
+// effectivePolicy collects the values of every LimitRange, in the given order, per item type
+// and resource. The result is sorted like limitTypes, then by resource name.
+func effectivePolicy(limitRanges []v1.LimitRange) []effectiveLimit {
+	var limits []effectiveLimit
+	index := map[v1.LimitType]map[v1.ResourceName]int{}
+	for _, limitRange := range limitRanges {
+		for i := range limitRange.Spec.Limits {
+			item := &limitRange.Spec.Limits[i]
+			for _, field := range policyFields {
+				for name, quantity := range resourceListFor(item, field) {
+					if index[item.Type] == nil {
+						index[item.Type] = map[v1.ResourceName]int{}
+					}
+					position, ok := index[item.Type][name]
+					if !ok {
+						position = len(limits)
+						index[item.Type][name] = position
+						limits = append(limits, effectiveLimit{limitType: item.Type, resource: name, values: map[limitField][]policyValue{}})
+					}
+					limits[position].values[field] = append(limits[position].values[field], policyValue{quantity, limitRange.Name})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(limits, func(i, j int) bool {
+		if limits[i].limitType != limits[j].limitType {
+			return limitTypeOrder(limits[i].limitType) < limitTypeOrder(limits[j].limitType)
+		}
+		return limits[i].resource < limits[j].resource
+	})
+	return limits
+}
+
+// limitTypeOrder returns the position of limitType in limitTypes, placing unknown types last
+func limitTypeOrder(limitType v1.LimitType) int {
+	for i, known := range limitTypes {
+		if known == limitType {
+			return i
+		}
+	}
+	return len(limitTypes)
+}
+
+// policyConflicts describes the values of different LimitRanges that contradict each other:
+// fields set to different values, bounds no value can satisfy and defaults outside the bounds
+func policyConflicts(limits []effectiveLimit) []string {
+	var conflicts []string
+	for _, limit := range limits {
+		subject := fmt.Sprintf("%s %s", limit.limitType, limit.resource)
+
+		for _, field := range policyFields {
+			if !hasDistinctValues(limit.values[field]) {
+				continue
+			}
+			settings := make([]string, 0, len(limit.values[field]))
+			for _, value := range limit.values[field] {
+				settings = append(settings, value.String())
+			}
+			message := fmt.Sprintf("%s %s is set to %s", subject, field, strings.Join(settings, ", "))
+			if field == limitFieldDefault || field == limitFieldDefaultRequest {
+				message += "; only one of them is filled in and which one depends on the admission order"
+			} else {
+				applied, _ := limit.effective(field)
+				message += fmt.Sprintf("; the most restrictive, %s, applies", applied)
+			}
+			conflicts = append(conflicts, message)
+		}
+
+		minimum, hasMin := limit.effective(limitFieldMin)
+		maximum, hasMax := limit.effective(limitFieldMax)
+		if hasMin && hasMax && minimum.quantity.Cmp(maximum.quantity) > 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s min %s is greater than max %s; nothing can satisfy both", subject, minimum, maximum))
+		}
+
+		for _, field := range []limitField{limitFieldDefaultRequest, limitFieldDefault} {
+			for _, value := range limit.values[field] {
+				if hasMax && value.quantity.Cmp(maximum.quantity) > 0 {
+					conflicts = append(conflicts, fmt.Sprintf("%s %s %s is above max %s", subject, field, value, maximum))
+				}
+				if hasMin && value.quantity.Cmp(minimum.quantity) < 0 {
+					conflicts = append(conflicts, fmt.Sprintf("%s %s %s is below min %s", subject, field, value, minimum))
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// hasDistinctValues reports whether values hold at least two different quantities
+func hasDistinctValues(values []policyValue) bool {
+	for _, value := range values {
+		if value.quantity.Cmp(values[0].quantity) != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newDescribeOptions(fakeClientset kubernetes.Interface) *DescribeOptions {
+	options := &DescribeOptions{
+		namespace: "default",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func namedLimitRange(name string, items ...v1.LimitRangeItem) *v1.LimitRange {
+	return &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.LimitRangeSpec{Limits: items},
+	}
+}
+
+func TestRunDescribe(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(
+		namedLimitRange("team",
+			v1.LimitRangeItem{
+				Type:           v1.LimitTypeContainer,
+				Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+			},
+		),
+		namedLimitRange("platform",
+			v1.LimitRangeItem{
+				Type: v1.LimitTypeContainer,
+				Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				Min:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+			},
+			v1.LimitRangeItem{
+				Type: v1.LimitTypePersistentVolumeClaim,
+				Max:  v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		),
+	)
+	options := newDescribeOptions(fakeClientset)
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t,
+		"Namespace:    default\n"+
+			"LimitRanges:  platform, team\n"+
+			"\n"+
+			"Effective limits:\n"+
+			"TYPE                    RESOURCE   MIN               MAX               DEFAULT-REQUEST   DEFAULT    RATIO\n"+
+			"Container               cpu        100m (platform)   1 (platform)      500m (team)       2 (team)   -\n"+
+			"PersistentVolumeClaim   storage    -                 10Gi (platform)   -                 -          -\n"+
+			"\n"+
+			"Conflicts:\n"+
+			"  - Container cpu max is set to 1 (platform), 2 (team); the most restrictive, 1 (platform), applies\n"+
+			"  - Container cpu default 2 (team) is above max 1 (platform)\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunDescribeAmbiguousDefaults(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(
+		namedLimitRange("a",
+			v1.LimitRangeItem{
+				Type:           v1.LimitTypeContainer,
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m")},
+			},
+		),
+		namedLimitRange("b",
+			v1.LimitRangeItem{
+				Type:           v1.LimitTypeContainer,
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m")},
+			},
+		),
+	)
+	options := newDescribeOptions(fakeClientset)
+
+	assert.NoError(t, options.Run())
+	// Equal defaults apply whichever LimitRange is first, different ones cannot be told apart
+	assert.Equal(t,
+		"Namespace:    default\n"+
+			"LimitRanges:  a, b\n"+
+			"\n"+
+			"Effective limits:\n"+
+			"TYPE        RESOURCE   MIN   MAX   DEFAULT-REQUEST   DEFAULT       RATIO\n"+
+			"Container   cpu        -     -     250m (a)          <ambiguous>   -\n"+
+			"\n"+
+			"Conflicts:\n"+
+			"  - Container cpu default is set to 1 (a), 500m (b); only one of them is filled in and which one depends on the admission order\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunDescribeNoLimitRanges(t *testing.T) {
+	options := newDescribeOptions(fake.NewSimpleClientset())
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "No LimitRanges in namespace default, containers are admitted without limits.\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestPolicyConflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		limitRanges []v1.LimitRange
+		expected    []string
+	}{
+		{
+			name: "equal values do not conflict",
+			limitRanges: []v1.LimitRange{
+				*namedLimitRange("a", v1.LimitRangeItem{Type: v1.LimitTypeContainer, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}}),
+				*namedLimitRange("b", v1.LimitRangeItem{Type: v1.LimitTypeContainer, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1000m")}}),
+			},
+		},
+		{
+			name: "min above max",
+			limitRanges: []v1.LimitRange{
+				*namedLimitRange("a", v1.LimitRangeItem{Type: v1.LimitTypePod, Min: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")}}),
+				*namedLimitRange("b", v1.LimitRangeItem{Type: v1.LimitTypePod, Max: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}}),
+			},
+			expected: []string{"Pod memory min 2Gi (a) is greater than max 1Gi (b); nothing can satisfy both"},
+		},
+		{
+			name: "different defaults and a default request below min",
+			limitRanges: []v1.LimitRange{
+				*namedLimitRange("a", v1.LimitRangeItem{
+					Type:           v1.LimitTypeContainer,
+					Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+				}),
+				*namedLimitRange("b", v1.LimitRangeItem{
+					Type:    v1.LimitTypeContainer,
+					Min:     v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m")},
+					Default: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+				}),
+			},
+			expected: []string{
+				"Container cpu default is set to 1 (a), 500m (b); only one of them is filled in and which one depends on the admission order",
+				"Container cpu defaultRequest 100m (a) is below min 200m (b)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policyConflicts(effectivePolicy(tt.limitRanges)))
+		})
+	}
+}
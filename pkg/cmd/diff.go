@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	diffExample = `
+    # Show how the container CPU limits of a live LimitRange would change
+    kubectl lr diff my-limitrange --namespace=my-namespace --max-cpu=2 --min-cpu=100m
+
+    # Compare the LimitRanges of a spec file with the cluster, failing a CI job on differences
+    kubectl lr diff -f limits.yaml || echo "limits drifted"
+    `
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// ExitError reports that a command finished with a specific exit code. Err, when set,
+// is the error to report; a nil Err means the command already printed its outcome.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the wrapped error, or the exit code when there is none
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// Unwrap returns the wrapped error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// DiffOptions holds information required to compare LimitRanges with their live versions
+type DiffOptions struct {
+	*LimitOptions
+}
+
+// NewCmdDiff creates a cobra command comparing the LimitRange built from flags with the live one.
+// Like kubectl diff, it exits with 1 when there are differences and with 2 on errors.
+func NewCmdDiff(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &DiffOptions{LimitOptions: NewLimitOptions(streams)}
+
+	cmd := &cobra.Command{
+		Use:           "diff NAME | -f FILENAME [flags]",
+		Short:         "Show the differences between a LimitRange and its live version",
+		Example:       diffExample,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				o.name = args[0]
+			}
+			if err := o.Complete(c, args); err != nil {
+				return &ExitError{Code: 2, Err: fmt.Errorf("completion error: %w", err)}
+			}
+			if err := o.Validate(); err != nil {
+				return &ExitError{Code: 2, Err: fmt.Errorf("validation error: %w", err)}
+			}
+			return o.Run()
+		},
+	}
+
+	// coverage:ignore-start
+	o.addLimitFlags(cmd)
+	cmd.Flags().BoolVar(&o.overwrite, "overwrite", false, "If true, compare with the result of --overwrite, which keeps live items of other types")
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Run prints a unified diff for every LimitRange that differs from its live version and
+// returns an ExitError with code 1 when any did
+func (o *DiffOptions) Run() error {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return &ExitError{Code: 2, Err: fmt.Errorf("failed to get Kubernetes client config: %w", err)}
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return &ExitError{Code: 2, Err: fmt.Errorf("failed to create Kubernetes clientset: %w", err)}
+	}
+
+	differences := false
+	for _, desired := range o.limitRangeObjects() {
+		live, err := clientset.CoreV1().LimitRanges(desired.Namespace).Get(context.TODO(), desired.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			live = nil
+		} else if err != nil {
+			return &ExitError{Code: 2, Err: fmt.Errorf("failed to get LimitRange: %w", err)}
+		}
+
+		if live != nil {
+			// A new --provenance timestamp alone is no difference, as with --overwrite
+			if restamped := withLiveTimestamp(desired, live); sameLimitRange(live, o.storedLimitRange(restamped, live)) {
+				desired = restamped
+			}
+		}
+		desired = o.storedLimitRange(desired, live)
+		alignQuantities(desired, live)
+
+		liveLines, err := diffLines(live)
+		if err != nil {
+			return &ExitError{Code: 2, Err: err}
+		}
+		desiredLines, err := diffLines(desired)
+		if err != nil {
+			return &ExitError{Code: 2, Err: err}
+		}
+
+		path := fmt.Sprintf("%s/%s", desired.Namespace, desired.Name)
+		if diff := unifiedDiff("live/"+path, "desired/"+path, liveLines, desiredLines); diff != "" {
+			fmt.Fprint(o.IOStreams.Out, diff)
+			differences = true
+		}
+	}
+
+	if differences {
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// storedLimitRange returns what the server would store for desired, which is not what was
+// typed: the derived defaults are filled in and, with --overwrite, desired is merged into live.
+// Labels and annotations of live that desired does not set, such as those kubectl apply or
+// Helm record, are kept either way; they are not this plugin's to manage.
+func (o *DiffOptions) storedLimitRange(desired, live *v1.LimitRange) *v1.LimitRange {
+	if o.overwrite && live != nil {
+		return overwrittenLimitRange(live, desired)
+	}
+	stored := desired.DeepCopy()
+	if live != nil {
+		stored.Labels = mergeInto(maps.Clone(live.Labels), desired.Labels)
+		stored.Annotations = mergeInto(maps.Clone(live.Annotations), desired.Annotations)
+	}
+	for i := range stored.Spec.Limits {
+		applyServerDefaults(&stored.Spec.Limits[i])
+	}
+	return stored
+}
+
+// diffLines renders the fields of limitRange this plugin manages as YAML lines: the name,
+// namespace, labels and annotations, and the spec. A nil limitRange renders as no lines at all.
+func diffLines(limitRange *v1.LimitRange) ([]string, error) {
+	if limitRange == nil {
+		return nil, nil
+	}
+	metadata := map[string]interface{}{
+		"name":      limitRange.Name,
+		"namespace": limitRange.Namespace,
+	}
+	if len(limitRange.Labels) > 0 {
+		metadata["labels"] = limitRange.Labels
+	}
+	if len(limitRange.Annotations) > 0 {
+		metadata["annotations"] = limitRange.Annotations
+	}
+	document := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "LimitRange",
+		"metadata":   metadata,
+		"spec":       limitRange.Spec,
+	}
+	output, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format LimitRange %s: %w", limitRange.Name, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(output), "\n"), "\n"), nil
+}
+
+// alignQuantities replaces every quantity of desired that equals the live quantity at the
+// same place with the live one, so "1000m" and "1" render identically in the diff
+func alignQuantities(desired, live *v1.LimitRange) {
+	if live == nil {
+		return
+	}
+	used := map[int]bool{}
+	for i := range desired.Spec.Limits {
+		item := &desired.Spec.Limits[i]
+		for j := range live.Spec.Limits {
+			if used[j] || live.Spec.Limits[j].Type != item.Type {
+				continue
+			}
+			used[j] = true
+			liveItem := &live.Spec.Limits[j]
+			for _, field := range []limitField{limitFieldMax, limitFieldMin, limitFieldDefault, limitFieldDefaultRequest, limitFieldRatio} {
+				desiredList, liveList := resourceListFor(item, field), resourceListFor(liveItem, field)
+				for name, quantity := range desiredList {
+					if liveQuantity, ok := liveList[name]; ok && quantity.Cmp(liveQuantity) == 0 {
+						desiredList[name] = liveQuantity.DeepCopy()
+					}
+				}
+			}
+			break
+		}
+	}
+}
+
+// diffOp is a single line of an edit script: ' ' keeps, '-' removes and '+' adds the line
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the differences between a and b in unified diff format,
+// or an empty string when they are equal
+func unifiedDiff(fromName, toName string, a, b []string) string {
+	ops := editScript(a, b)
+
+	// Find the ranges of ops that belong to a hunk: every change plus its context,
+	// merging hunks whose context overlaps
+	type span struct{ start, end int }
+	var hunks []span
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start := max(i-diffContextLines, 0)
+		end := min(i+diffContextLines+1, len(ops))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+		} else {
+			hunks = append(hunks, span{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	aLine, bLine, next := 0, 0, 0
+	for _, hunk := range hunks {
+		// Count the lines of a and b before the hunk
+		for ; next < hunk.start; next++ {
+			if ops[next].kind != '+' {
+				aLine++
+			}
+			if ops[next].kind != '-' {
+				bLine++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[hunk.start:hunk.end] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aLine, aLen), hunkRange(bLine, bLen))
+		for _, op := range ops[hunk.start:hunk.end] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.line)
+		}
+	}
+	return out.String()
+}
+
+// hunkRange formats the start and length of a hunk the way diff -u does
+func hunkRange(before, length int) string {
+	start := before + 1
+	if length == 0 {
+		start = before
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+// editScript turns a into b through the longest common subsequence of their lines
+func editScript(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newDiffOptions(fakeClientset kubernetes.Interface) *DiffOptions {
+	options := &LimitOptions{
+		name:      "test-limitrange",
+		namespace: "default",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return &DiffOptions{LimitOptions: options}
+}
+
+func liveCPULimitRange(maxCPU, minCPU string) *v1.LimitRange {
+	return &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-limitrange", Namespace: "default", ResourceVersion: "1"},
+		Spec: v1.LimitRangeSpec{
+			Limits: []v1.LimitRangeItem{
+				{
+					Type:           v1.LimitTypeContainer,
+					Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse(maxCPU)},
+					Min:            v1.ResourceList{v1.ResourceCPU: resource.MustParse(minCPU)},
+					Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse(maxCPU)},
+					DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse(maxCPU)},
+				},
+			},
+		},
+	}
+}
+
+func exitCode(err error) int {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return -1
+}
+
+func TestRunDiffEqualQuantities(t *testing.T) {
+	options := newDiffOptions(fake.NewSimpleClientset(liveCPULimitRange("1", "100m")))
+	options.maxCPU = "1000m"
+	options.minCPU = "0.1"
+
+	assert.NoError(t, options.Run())
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunDiffChanged(t *testing.T) {
+	options := newDiffOptions(fake.NewSimpleClientset(liveCPULimitRange("1", "100m")))
+	options.maxCPU = "2"
+	options.minCPU = "100m"
+
+	err := options.Run()
+	assert.Equal(t, 1, exitCode(err))
+	assert.NoError(t, errors.Unwrap(err))
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "--- live/default/test-limitrange\n+++ desired/default/test-limitrange\n")
+	assert.Contains(t, output, "-      cpu: \"1\"\n")
+	assert.Contains(t, output, "+      cpu: \"2\"\n")
+	assert.NotContains(t, output, "-      cpu: 100m")
+}
+
+func TestRunDiffMissingLiveObject(t *testing.T) {
+	options := newDiffOptions(fake.NewSimpleClientset())
+	options.maxCPU = "2"
+
+	assert.Equal(t, 1, exitCode(options.Run()))
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "@@ -0,0 +1,")
+	assert.Contains(t, output, "+  name: test-limitrange\n")
+	assert.NotContains(t, output, "\n-")
+}
+
+func TestRunDiffOverwriteKeepsOtherTypes(t *testing.T) {
+	live := liveCPULimitRange("1", "100m")
+	live.Spec.Limits = append(live.Spec.Limits, v1.LimitRangeItem{
+		Type: v1.LimitTypePersistentVolumeClaim,
+		Max:  v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+	})
+
+	options := newDiffOptions(fake.NewSimpleClientset(live))
+	options.maxCPU = "1"
+	options.minCPU = "100m"
+	assert.Equal(t, 1, exitCode(options.Run()), "without --overwrite the PVC item is removed")
+
+	options = newDiffOptions(fake.NewSimpleClientset(live))
+	options.maxCPU = "1"
+	options.minCPU = "100m"
+	options.overwrite = true
+	assert.NoError(t, options.Run())
+}
+
+func TestRunDiffMetadata(t *testing.T) {
+	live := liveCPULimitRange("1", "100m")
+	live.Labels = map[string]string{"team": "payments"}
+
+	options := newDiffOptions(fake.NewSimpleClientset(live))
+	options.maxCPU = "1"
+	options.minCPU = "100m"
+	options.labels = []string{"team=checkout"}
+	options.annotations = []string{"owner=checkout@example.com"}
+	options.saveConfig = true
+	assert.Equal(t, 1, exitCode(options.Run()))
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "+  annotations:\n+    kubectl.kubernetes.io/last-applied-configuration: |\n")
+	assert.Contains(t, output, "+    owner: checkout@example.com\n")
+	assert.Contains(t, output, "-    team: payments\n+    team: checkout\n")
+}
+
+func TestRunDiffForeignMetadata(t *testing.T) {
+	live := liveCPULimitRange("1", "100m")
+	live.Labels = map[string]string{"app.kubernetes.io/managed-by": "Helm"}
+	live.Annotations = map[string]string{v1.LastAppliedConfigAnnotation: `{"kind":"LimitRange"}`}
+
+	options := newDiffOptions(fake.NewSimpleClientset(live))
+	options.maxCPU = "1"
+	options.minCPU = "100m"
+	assert.NoError(t, options.Run(), "labels and annotations set by others are no difference")
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunDiffProvenanceTimestamp(t *testing.T) {
+	live := liveCPULimitRange("1", "100m")
+	live.Annotations = map[string]string{
+		provenanceVersionAnnotation:   "dev",
+		provenanceTimestampAnnotation: "2025-03-14T14:09:26Z",
+	}
+	newOptions := func(maxCPU string) *DiffOptions {
+		options := newDiffOptions(fake.NewSimpleClientset(live))
+		options.maxCPU = maxCPU
+		options.minCPU = "100m"
+		options.provenance = true
+		options.provenanceAnnotations = map[string]string{
+			provenanceVersionAnnotation:   "dev",
+			provenanceTimestampAnnotation: "2025-06-01T08:00:00Z",
+		}
+		return options
+	}
+
+	options := newOptions("1")
+	assert.NoError(t, options.Run(), "only the timestamp would change")
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+
+	options = newOptions("2")
+	assert.Equal(t, 1, exitCode(options.Run()))
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "-    kubectl-lr/timestamp: \"2025-03-14T14:09:26Z\"\n+    kubectl-lr/timestamp: \"2025-06-01T08:00:00Z\"\n")
+}
+
+func TestRunDiffGetError(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	fakeClientset.PrependReactor("get", "limitranges", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	options := newDiffOptions(fakeClientset)
+	options.maxCPU = "1"
+
+	err := options.Run()
+	assert.Equal(t, 2, exitCode(err))
+	assert.EqualError(t, err, "failed to get LimitRange: connection refused")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []string
+		expected string
+	}{
+		{
+			name:     "equal",
+			a:        []string{"a", "b"},
+			b:        []string{"a", "b"},
+			expected: "",
+		},
+		{
+			name:     "from empty",
+			b:        []string{"a", "b"},
+			expected: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:     "changed line with context",
+			a:        []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+			b:        []string{"1", "2", "3", "4", "x", "6", "7", "8", "9"},
+			expected: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			a:    []string{"a", "1", "2", "3", "4", "5", "6", "7", "b"},
+			b:    []string{"A", "1", "2", "3", "4", "5", "6", "7", "B"},
+			expected: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, unifiedDiff("old", "new", tt.a, tt.b))
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	editExample = `
+    # Edit the values of a LimitRange in the editor from KUBE_EDITOR or EDITOR
+    kubectl lr edit my-limitrange --namespace=my-namespace
+
+    # Use a specific editor
+    KUBE_EDITOR="code --wait" kubectl lr edit my-limitrange
+    `
+)
+
+// editInstructions is the comment placed above the table opened in the editor
+const editInstructions = `# Please edit the limits of limitrange %q in namespace %q below.
+# Each row sets the values of one resource for one type (Container, Pod or
+# PersistentVolumeClaim). Use "-" to leave a value unset and delete a row to
+# remove the resource. Lines starting with '#' are ignored, and an empty file
+# or a file without changes cancels the edit.
+#
+`
+
+// EditOptions holds information required to edit a LimitRange
+type EditOptions struct {
+	configFlags   *genericclioptions.ConfigFlags
+	namespace     string
+	name          string
+	IOStreams     genericclioptions.IOStreams
+	clientsetFunc func(config *rest.Config) (kubernetes.Interface, error)
+	// editFunc opens path in an editor and returns once the editor is closed
+	editFunc func(path string) error
+}
+
+// NewEditOptions initializes an instance of EditOptions with default values
+func NewEditOptions(streams genericclioptions.IOStreams) *EditOptions {
+	return &EditOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+		clientsetFunc: func(config *rest.Config) (kubernetes.Interface, error) {
+			return kubernetes.NewForConfig(config)
+		},
+		editFunc: func(path string) error {
+			return launchEditor(streams, path)
+		},
+	}
+}
+
+// NewCmdEdit creates a cobra command editing the values of a live LimitRange as a table
+func NewCmdEdit(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewEditOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "edit NAME [flags]",
+		Short: "Edit the values of a LimitRange as a table in your editor",
+		Long: "Open the values of a live LimitRange as a table in the editor named by KUBE_EDITOR or EDITOR " +
+			"(vi by default, notepad on Windows). Invalid edits reopen the editor with the errors on top; valid ones update the " +
+			"LimitRange unless it was changed by someone else in the meantime.",
+		Example:      editExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.configFlags.AddFlags(cmd.Flags())
+	if nsFlag := cmd.Flag("namespace"); nsFlag != nil {
+		nsFlag.Shorthand = "n"
+	}
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Complete sets the name to edit and resolves the namespace
+func (o *EditOptions) Complete(_ *cobra.Command, args []string) error {
+	o.name = args[0]
+	var err error
+	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to get current namespace: %w", err)
+	}
+	return nil
+}
+
+// Run opens the LimitRange in the editor until the edit is valid or cancelled, then updates it
+func (o *EditOptions) Run() error {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	client := clientset.CoreV1().LimitRanges(o.namespace)
+	live, err := client.Get(context.TODO(), o.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get LimitRange: %w", err)
+	}
+
+	file, err := os.CreateTemp("", "kubectl-lr-edit-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	path := file.Name()
+	file.Close()
+	keepFile := false
+	defer func() {
+		if !keepFile {
+			os.Remove(path)
+		}
+	}()
+
+	table, err := formatEditTable(live.Spec.Limits)
+	if err != nil {
+		return err
+	}
+	var validationErr error
+	var edited *v1.LimitRange
+	for {
+		presented := editFileContent(live, table, validationErr)
+		if err := os.WriteFile(path, []byte(presented), 0o600); err != nil {
+			return fmt.Errorf("failed to write temporary file: %w", err)
+		}
+		if err := o.editFunc(path); err != nil {
+			return fmt.Errorf("failed to run editor: %w", err)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read temporary file: %w", err)
+		}
+
+		table = stripComments(string(content))
+		if strings.TrimSpace(table) == "" || string(content) == presented {
+			fmt.Fprintln(o.IOStreams.Out, "Edit cancelled, no changes made.")
+			return nil
+		}
+
+		edited = live.DeepCopy()
+		edited.Spec.Limits, validationErr = parseEditTable(table)
+		if validationErr == nil {
+			validationErr = validateLimitRangeValues(edited, func(index int) valueNamer {
+				return editValueNamer(edited.Spec.Limits[index])
+			})
+		}
+		if validationErr == nil {
+			break
+		}
+		// Reopen the editor with the errors on top of what was written
+	}
+
+	for i := range edited.Spec.Limits {
+		applyServerDefaults(&edited.Spec.Limits[i])
+	}
+	if apiequality.Semantic.DeepEqual(live.Spec.Limits, edited.Spec.Limits) {
+		fmt.Fprintln(o.IOStreams.Out, "Edit cancelled, no changes made.")
+		return nil
+	}
+
+	// The resourceVersion read above makes the update fail if the LimitRange changed meanwhile
+	if _, err := client.Update(context.TODO(), edited, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			keepFile = true
+			return fmt.Errorf("limitrange %q was changed while it was being edited, your changes were saved to %s: %w", o.name, path, err)
+		}
+		return fmt.Errorf("failed to update LimitRange: %w", err)
+	}
+
+	fmt.Fprintf(o.IOStreams.Out, "limitrange.core %q edited\n", o.name)
+	return nil
+}
+
+// editFileContent returns the instructions, the error of the previous attempt if any, and the table
+func editFileContent(limitRange *v1.LimitRange, table string, validationErr error) string {
+	var content strings.Builder
+	fmt.Fprintf(&content, editInstructions, limitRange.Name, limitRange.Namespace)
+	if validationErr != nil {
+		fmt.Fprintf(&content, "# The edited limits are not valid, fix them or empty the file to cancel:\n#   %s\n#\n", validationErr)
+	}
+	content.WriteString(table)
+	return content.String()
+}
+
+// formatEditTable formats items as a table with one row per resource. Container CPU and memory
+// always get a row, so they can be filled in even when the LimitRange does not set them yet.
+func formatEditTable(items []v1.LimitRangeItem) (string, error) {
+	var rows [][]string
+	hasContainer := map[v1.ResourceName]bool{}
+	for _, item := range items {
+		for _, row := range limitItemRows(item) {
+			if item.Type == v1.LimitTypeContainer {
+				hasContainer[v1.ResourceName(row[1])] = true
+			}
+			rows = append(rows, row)
+		}
+	}
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		if !hasContainer[name] {
+			rows = append(rows, []string{string(v1.LimitTypeContainer), string(name), "-", "-", "-", "-", "-"})
+		}
+	}
+
+	var table bytes.Buffer
+	w := printers.GetNewTabWriter(&table)
+	fmt.Fprintf(w, "%s\n", strings.Join(limitTableHeader, "\t"))
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\n", strings.Join(row, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return table.String(), nil
+}
+
+// stripComments removes the lines starting with '#' from content
+func stripComments(content string) string {
+	var kept []string
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
+
+// parseEditTable reads the items back from a table in the layout of formatEditTable.
+// Rows with every value unset are dropped, and items keep the order their type first appears in.
+func parseEditTable(table string) ([]v1.LimitRangeItem, error) {
+	var items []v1.LimitRangeItem
+	seen := map[string]bool{}
+	for number, line := range strings.Split(table, "\n") {
+		columns := strings.Fields(line)
+		if len(columns) == 0 || (len(columns) == len(limitTableHeader) && columns[0] == limitTableHeader[0]) {
+			continue
+		}
+		if len(columns) != len(limitTableHeader) {
+			return nil, fmt.Errorf("line %d: expected %d columns (%s), found %d", number+1, len(limitTableHeader), strings.Join(limitTableHeader, " "), len(columns))
+		}
+
+		limitType, known := limitTypeNames[strings.ToLower(columns[0])]
+		if !known {
+			return nil, fmt.Errorf("line %d: unsupported limit type %q, must be one of Container, Pod or PersistentVolumeClaim", number+1, columns[0])
+		}
+		name := v1.ResourceName(columns[1])
+		if errs := validation.IsQualifiedName(string(name)); len(errs) > 0 {
+			return nil, fmt.Errorf("line %d: invalid resource name %q: %s", number+1, name, strings.Join(errs, "; "))
+		}
+		key := string(limitType) + "/" + string(name)
+		if seen[key] {
+			return nil, fmt.Errorf("line %d: %s %s is listed more than once", number+1, limitType, name)
+		}
+		seen[key] = true
+
+		var item *v1.LimitRangeItem
+		for i, field := range policyFields {
+			value := columns[i+2]
+			if value == "-" {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q: %w", number+1, editValueName(limitType, name, field), value, err)
+			}
+			if item == nil {
+				item = editItemFor(&items, limitType)
+			}
+			list := resourceListFor(item, field)
+			list[name] = quantity
+		}
+	}
+	return items, nil
+}
+
+// editValueNamer names the values of item in errors by the row and column of the table they
+// were edited in, e.g. "Container memory MAX"
+func editValueNamer(item v1.LimitRangeItem) valueNamer {
+	return func(field limitField, name v1.ResourceName) (string, bool) {
+		return editValueName(item.Type, name, field), false
+	}
+}
+
+// editValueName returns the type, resource and column that hold the value of field
+func editValueName(limitType v1.LimitType, name v1.ResourceName, field limitField) string {
+	for i, f := range policyFields {
+		if f == field {
+			return fmt.Sprintf("%s %s %s", limitType, name, limitTableHeader[i+2])
+		}
+	}
+	return fmt.Sprintf("%s %s %s", limitType, name, field)
+}
+
+// editItemFor returns the item of limitType in items, appending one with empty lists when missing
+func editItemFor(items *[]v1.LimitRangeItem, limitType v1.LimitType) *v1.LimitRangeItem {
+	for i := range *items {
+		if (*items)[i].Type == limitType {
+			return &(*items)[i]
+		}
+	}
+	*items = append(*items, v1.LimitRangeItem{
+		Type:                 limitType,
+		Max:                  v1.ResourceList{},
+		Min:                  v1.ResourceList{},
+		Default:              v1.ResourceList{},
+		DefaultRequest:       v1.ResourceList{},
+		MaxLimitRequestRatio: v1.ResourceList{},
+	})
+	return &(*items)[len(*items)-1]
+}
+
+// launchEditor opens path in the editor of editorCommand, attached to streams
+func launchEditor(streams genericclioptions.IOStreams, path string) error {
+	args := editorCommand(runtime.GOOS, os.Getenv, path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = streams.In, streams.Out, streams.ErrOut
+	return cmd.Run()
+}
+
+// editorCommand returns the command line opening path in the editor named by KUBE_EDITOR or
+// EDITOR, falling back to vi, or notepad on Windows. As in kubectl edit, an editor with
+// arguments such as "code --wait" is split on spaces, and one with quotes or backslashes is
+// left to the shell: SHELL or /bin/sh, or cmd on Windows.
+func editorCommand(goos string, getenv func(string) string, path string) []string {
+	editor := getenv("KUBE_EDITOR")
+	if editor == "" {
+		editor = getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if goos == "windows" {
+			editor = "notepad"
+		}
+	}
+	if !strings.ContainsAny(editor, `"'\`) {
+		return append(strings.Fields(editor), path)
+	}
+	if goos == "windows" {
+		return []string{"cmd", "/C", editor + ` "` + path + `"`}
+	}
+	shell := getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	// The path is passed as $1 so that it needs no quoting
+	return []string{shell, "-c", editor + ` "$1"`, shell, path}
+}
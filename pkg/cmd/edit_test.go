@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// scriptedEditor returns an editFunc that replaces the content of the file with each of
+// edits in turn, recording what the editor was opened with
+func scriptedEditor(opened *[]string, edits ...func(string) string) func(string) error {
+	return func(path string) error {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		*opened = append(*opened, string(content))
+		edit := edits[0]
+		edits = edits[1:]
+		return os.WriteFile(path, []byte(edit(string(content))), 0o600)
+	}
+}
+
+func newEditOptions(fakeClientset kubernetes.Interface, editFunc func(string) error) *EditOptions {
+	options := &EditOptions{
+		name:      "test-limitrange",
+		namespace: "default",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+		editFunc:  editFunc,
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func editableLimitRange() *v1.LimitRange {
+	return &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-limitrange", Namespace: "default", ResourceVersion: "1"},
+		Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{
+			Type:           v1.LimitTypeContainer,
+			Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			Min:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+			Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		}}},
+	}
+}
+
+func TestRunEditReopensOnInvalidEdit(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(editableLimitRange())
+	var opened []string
+	options := newEditOptions(fakeClientset, scriptedEditor(&opened,
+		func(content string) string {
+			return strings.Replace(content, "Container   memory     -      -", "Container   memory     2Gi    1Gi", 1)
+		},
+		func(content string) string {
+			return strings.Replace(content, "2Gi    1Gi", "128Mi  1Gi", 1)
+		},
+	))
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "limitrange.core \"test-limitrange\" edited\n", options.IOStreams.Out.(*bytes.Buffer).String())
+
+	if assert.Len(t, opened, 2) {
+		assert.Equal(t,
+			"TYPE        RESOURCE   MIN    MAX   DEFAULT-REQUEST   DEFAULT   RATIO\n"+
+				"Container   cpu        100m   1     1                 1         -\n"+
+				"Container   memory     -      -     -                 -         -\n",
+			stripComments(opened[0]))
+		assert.NotContains(t, opened[0], "not valid")
+		assert.Contains(t, opened[1], "# The edited limits are not valid, fix them or empty the file to cancel:\n"+
+			"#   Container memory MIN (2Gi) must not be greater than Container memory MAX (1Gi)\n")
+	}
+
+	lr, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	if assert.NoError(t, err) && assert.Len(t, lr.Spec.Limits, 1) {
+		minMemory := lr.Spec.Limits[0].Min[v1.ResourceMemory]
+		defaultMemory := lr.Spec.Limits[0].Default[v1.ResourceMemory]
+		assert.Equal(t, "128Mi", minMemory.String())
+		assert.Equal(t, "1Gi", defaultMemory.String(), "the server default is filled in from max")
+	}
+}
+
+func TestRunEditCancelled(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(string) string
+	}{
+		{"unchanged file", func(content string) string { return content }},
+		{"empty file", func(_ string) string { return "" }},
+		{"same values", func(content string) string { return strings.Replace(content, "100m", "0.1", 1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClientset := fake.NewSimpleClientset(editableLimitRange())
+			var opened []string
+			options := newEditOptions(fakeClientset, scriptedEditor(&opened, tt.edit))
+
+			assert.NoError(t, options.Run())
+			assert.Equal(t, "Edit cancelled, no changes made.\n", options.IOStreams.Out.(*bytes.Buffer).String())
+			for _, action := range fakeClientset.Actions() {
+				assert.NotEqual(t, "update", action.GetVerb())
+			}
+		})
+	}
+}
+
+func TestRunEditConflict(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(editableLimitRange())
+	fakeClientset.PrependReactor("update", "limitranges", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "limitranges"}, "test-limitrange", nil)
+	})
+	var opened []string
+	options := newEditOptions(fakeClientset, scriptedEditor(&opened,
+		func(content string) string { return strings.Replace(content, "100m", "200m", 1) },
+	))
+
+	err := options.Run()
+	if assert.ErrorContains(t, err, `limitrange "test-limitrange" was changed while it was being edited, your changes were saved to `) {
+		path := strings.TrimSpace(strings.SplitN(strings.SplitAfter(err.Error(), "saved to ")[1], ":", 2)[0])
+		content, readErr := os.ReadFile(path)
+		assert.NoError(t, readErr)
+		assert.Contains(t, string(content), "200m")
+		os.Remove(path)
+	}
+}
+
+func TestParseEditTable(t *testing.T) {
+	tests := []struct {
+		name        string
+		table       string
+		expected    []v1.LimitRangeItem
+		expectedErr string
+	}{
+		{
+			name: "rows of several types",
+			table: "TYPE RESOURCE MIN MAX DEFAULT-REQUEST DEFAULT RATIO\n" +
+				"Container cpu 100m 1 - - 4\n" +
+				"pvc storage - 10Gi - - -\n" +
+				"Container memory - - - - -\n",
+			expected: []v1.LimitRangeItem{
+				{
+					Type:                 v1.LimitTypeContainer,
+					Min:                  v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+					Max:                  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					Default:              v1.ResourceList{},
+					DefaultRequest:       v1.ResourceList{},
+					MaxLimitRequestRatio: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+				},
+				{
+					Type:                 v1.LimitTypePersistentVolumeClaim,
+					Min:                  v1.ResourceList{},
+					Max:                  v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+					Default:              v1.ResourceList{},
+					DefaultRequest:       v1.ResourceList{},
+					MaxLimitRequestRatio: v1.ResourceList{},
+				},
+			},
+		},
+		{
+			name:        "missing column",
+			table:       "Container cpu 100m 1 - -\n",
+			expectedErr: "line 1: expected 7 columns (TYPE RESOURCE MIN MAX DEFAULT-REQUEST DEFAULT RATIO), found 6",
+		},
+		{
+			name:        "unknown type",
+			table:       "\nNode cpu 100m 1 - - -\n",
+			expectedErr: `line 2: unsupported limit type "Node", must be one of Container, Pod or PersistentVolumeClaim`,
+		},
+		{
+			name:        "invalid quantity",
+			table:       "Container cpu lots 1 - - -\n",
+			expectedErr: `line 1: invalid Container cpu MIN "lots": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+		},
+		{
+			name:        "duplicate row",
+			table:       "Container cpu - 1 - - -\nContainer cpu - 2 - - -\n",
+			expectedErr: "line 2: Container cpu is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parseEditTable(tt.table)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, items)
+			}
+		})
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		env      map[string]string
+		expected []string
+	}{
+		{
+			name:     "default",
+			goos:     "linux",
+			expected: []string{"vi", "/tmp/edit.txt"},
+		},
+		{
+			name:     "default on windows",
+			goos:     "windows",
+			expected: []string{"notepad", "/tmp/edit.txt"},
+		},
+		{
+			name:     "KUBE_EDITOR before EDITOR",
+			goos:     "linux",
+			env:      map[string]string{"KUBE_EDITOR": "nano", "EDITOR": "emacs"},
+			expected: []string{"nano", "/tmp/edit.txt"},
+		},
+		{
+			name:     "editor with arguments",
+			goos:     "darwin",
+			env:      map[string]string{"EDITOR": "code --wait"},
+			expected: []string{"code", "--wait", "/tmp/edit.txt"},
+		},
+		{
+			name:     "quoted editor through the shell",
+			goos:     "linux",
+			env:      map[string]string{"EDITOR": `"/opt/my editor/bin/edit" -w`, "SHELL": "/bin/zsh"},
+			expected: []string{"/bin/zsh", "-c", `"/opt/my editor/bin/edit" -w "$1"`, "/bin/zsh", "/tmp/edit.txt"},
+		},
+		{
+			name:     "windows path through cmd",
+			goos:     "windows",
+			env:      map[string]string{"KUBE_EDITOR": `C:\Tools\edit.exe`},
+			expected: []string{"cmd", "/C", `C:\Tools\edit.exe "/tmp/edit.txt"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.expected, editorCommand(tt.goos, getenv, "/tmp/edit.txt"))
+		})
+	}
+}
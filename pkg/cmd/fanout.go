@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultConcurrency is the number of namespaces a fan-out creates LimitRanges in at the same time
+const defaultConcurrency = 5
+
+// namespaceResult is the outcome of creating the LimitRanges in one namespace of a fan-out
+type namespaceResult struct {
+	namespace string
+	output    string
+	err       error
+}
+
+// fanOut reports whether the LimitRanges go to more than a single namespace
+func (o *LimitOptions) fanOut() bool {
+	return len(o.namespaces) > 1 || o.namespaceSelector != "" || o.allNamespaces
+}
+
+// validateFanOut checks the flags that select the namespaces to create the LimitRanges in
+func (o *LimitOptions) validateFanOut() error {
+	for _, namespace := range o.namespaces {
+		if namespace == "" {
+			return fmt.Errorf("namespace cannot be empty")
+		}
+	}
+	if o.allNamespaces && (len(o.namespaces) > 0 || o.namespaceSelector != "") {
+		return fmt.Errorf("--all-namespaces cannot be combined with --namespace or --namespace-selector")
+	}
+	if o.namespaceSelector != "" && len(o.namespaces) > 0 {
+		return fmt.Errorf("--namespace-selector cannot be combined with --namespace")
+	}
+	for _, pattern := range o.excludeNamespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --exclude-namespace pattern %q: %w", pattern, err)
+		}
+	}
+	if o.fanOut() && o.concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	return nil
+}
+
+// runFanOut creates the LimitRanges in every target namespace using a bounded pool of workers.
+// A failing namespace does not stop the others; the summary lists the result of each one.
+func (o *LimitOptions) runFanOut(clientset kubernetes.Interface) error {
+	namespaces, err := o.targetNamespaces(clientset)
+	if err != nil {
+		return err
+	}
+	if len(namespaces) == 0 {
+		fmt.Fprintln(o.IOStreams.ErrOut, "No namespaces found")
+		return nil
+	}
+
+	results := make([]namespaceResult, len(namespaces))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(o.concurrency, len(namespaces)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = o.runInNamespace(clientset, namespaces[i])
+			}
+		}()
+	}
+	for i := range namespaces {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return o.printFanOutSummary(results)
+}
+
+// runInNamespace creates the LimitRanges in namespace, capturing what would be printed
+func (o *LimitOptions) runInNamespace(clientset kubernetes.Interface, namespace string) namespaceResult {
+	var output bytes.Buffer
+	worker := *o
+	worker.namespace = namespace
+	worker.explicitNamespace = true
+	worker.IOStreams.Out = &output
+
+	err := worker.sendLimitRanges(clientset)
+	return namespaceResult{namespace: namespace, output: output.String(), err: err}
+}
+
+// targetNamespaces returns the sorted namespaces given with --namespace, or the ones matching
+// --namespace-selector or --all-namespaces, leaving out those matching --exclude-namespace
+func (o *LimitOptions) targetNamespaces(clientset kubernetes.Interface) ([]string, error) {
+	candidates := o.namespaces
+	if len(candidates) == 0 {
+		list, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: o.namespaceSelector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		for _, namespace := range list.Items {
+			// Nothing can be created in a namespace that is being deleted
+			if namespace.Status.Phase == v1.NamespaceTerminating {
+				continue
+			}
+			candidates = append(candidates, namespace.Name)
+		}
+	}
+
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, namespace := range candidates {
+		if seen[namespace] || o.isExcludedNamespace(namespace) {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// isExcludedNamespace reports whether namespace matches one of the --exclude-namespace patterns
+func (o *LimitOptions) isExcludedNamespace(namespace string) bool {
+	for _, pattern := range o.excludeNamespaces {
+		// Patterns were checked by Validate
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// printFanOutSummary prints one row per namespace with its result and returns an error if any
+// namespace failed. Objects requested with --output go to Out and the summary to ErrOut.
+func (o *LimitOptions) printFanOutSummary(results []namespaceResult) error {
+	summary := o.IOStreams.Out
+	if o.output != "" {
+		summary = o.IOStreams.ErrOut
+		printed := false
+		for _, result := range results {
+			if result.output == "" {
+				continue
+			}
+			if printed && o.output == "yaml" {
+				fmt.Fprintln(o.IOStreams.Out, "---")
+			}
+			fmt.Fprint(o.IOStreams.Out, result.output)
+			printed = true
+		}
+	}
+
+	failed := 0
+	w := printers.GetNewTabWriter(summary)
+	fmt.Fprintln(w, "NAMESPACE\tRESULT")
+	for _, result := range results {
+		var lines []string
+		switch {
+		case result.err != nil:
+			failed++
+			lines = []string{"error: " + result.err.Error()}
+		case o.output != "":
+			lines = []string{"ok"}
+		default:
+			lines = strings.Split(strings.TrimSuffix(result.output, "\n"), "\n")
+		}
+		for i, line := range lines {
+			namespace := ""
+			if i == 0 {
+				namespace = result.namespace
+			}
+			fmt.Fprintf(w, "%s\t%s\n", namespace, line)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed in %d of %d namespaces", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFanOutOptions(fakeClientset kubernetes.Interface) *LimitOptions {
+	options := &LimitOptions{
+		name:        "test-limitrange",
+		namespace:   "default",
+		maxCPU:      "1",
+		concurrency: 2,
+		IOStreams:   genericclioptions.IOStreams{Out: new(bytes.Buffer), ErrOut: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func fanOutNamespaces() *fake.Clientset {
+	namespace := func(name string, labels map[string]string, phase v1.NamespacePhase) *v1.Namespace {
+		return &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status:     v1.NamespaceStatus{Phase: phase},
+		}
+	}
+	return fake.NewSimpleClientset(
+		namespace("team-a", map[string]string{"tier": "team"}, v1.NamespaceActive),
+		namespace("team-b", map[string]string{"tier": "team"}, v1.NamespaceActive),
+		namespace("team-old", map[string]string{"tier": "team"}, v1.NamespaceTerminating),
+		namespace("kube-system", nil, v1.NamespaceActive),
+		namespace("kube-public", nil, v1.NamespaceActive),
+	)
+}
+
+func createdIn(t *testing.T, clientset kubernetes.Interface, namespaces ...string) {
+	for _, namespace := range namespaces {
+		_, err := clientset.CoreV1().LimitRanges(namespace).Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+		assert.NoError(t, err, "LimitRange missing in %s", namespace)
+	}
+}
+
+func TestRunFanOutContinuesAfterFailure(t *testing.T) {
+	fakeClientset := fanOutNamespaces()
+	fakeClientset.PrependReactor("create", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "team-b" {
+			return true, nil, errors.New("quota exceeded")
+		}
+		return false, nil, nil
+	})
+	options := newFanOutOptions(fakeClientset)
+	options.namespaces = []string{"team-b", "team-a", "kube-public", "team-a"}
+
+	assert.EqualError(t, options.Run(), "failed in 1 of 3 namespaces")
+	assert.Equal(t,
+		"NAMESPACE     RESULT\n"+
+			"kube-public   limitrange.core \"test-limitrange\" created\n"+
+			"team-a        limitrange.core \"test-limitrange\" created\n"+
+			"team-b        error: failed to create LimitRange: quota exceeded\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+	createdIn(t, fakeClientset, "kube-public", "team-a")
+}
+
+func TestRunFanOutNamespaceSelector(t *testing.T) {
+	fakeClientset := fanOutNamespaces()
+	options := newFanOutOptions(fakeClientset)
+	options.namespaceSelector = "tier=team"
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t,
+		"NAMESPACE   RESULT\n"+
+			"team-a      limitrange.core \"test-limitrange\" created\n"+
+			"team-b      limitrange.core \"test-limitrange\" created\n",
+		options.IOStreams.Out.(*bytes.Buffer).String(), "terminating namespaces are skipped")
+	createdIn(t, fakeClientset, "team-a", "team-b")
+}
+
+func TestRunFanOutAllNamespacesDryRun(t *testing.T) {
+	fakeClientset := fanOutNamespaces()
+	options := newFanOutOptions(fakeClientset)
+	options.allNamespaces = true
+	options.excludeNamespaces = []string{"kube-*", "team-b"}
+	options.dryRun = "client"
+	options.output = "yaml"
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "NAMESPACE   RESULT\nteam-a      ok\n", options.IOStreams.ErrOut.(*bytes.Buffer).String())
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "namespace: team-a\n")
+	assert.NotContains(t, output, "---")
+
+	list, err := fakeClientset.CoreV1().LimitRanges(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
+
+func TestRunFanOutNoNamespaces(t *testing.T) {
+	options := newFanOutOptions(fanOutNamespaces())
+	options.namespaceSelector = "tier=none"
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "No namespaces found\n", options.IOStreams.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestValidateFanOut(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     LimitOptions
+		expectedErr string
+	}{
+		{
+			name:    "several namespaces",
+			options: LimitOptions{namespaces: []string{"a", "b"}, excludeNamespaces: []string{"kube-*"}, concurrency: 1},
+		},
+		{
+			name:        "empty namespace",
+			options:     LimitOptions{namespaces: []string{"a", ""}, concurrency: 1},
+			expectedErr: "namespace cannot be empty",
+		},
+		{
+			name:        "all namespaces with a namespace",
+			options:     LimitOptions{namespaces: []string{"a"}, allNamespaces: true, concurrency: 1},
+			expectedErr: "--all-namespaces cannot be combined with --namespace or --namespace-selector",
+		},
+		{
+			name:        "selector with a namespace",
+			options:     LimitOptions{namespaces: []string{"a"}, namespaceSelector: "tier=team", concurrency: 1},
+			expectedErr: "--namespace-selector cannot be combined with --namespace",
+		},
+		{
+			name:        "invalid pattern",
+			options:     LimitOptions{allNamespaces: true, excludeNamespaces: []string{"kube-["}, concurrency: 1},
+			expectedErr: `invalid --exclude-namespace pattern "kube-[": syntax error in pattern`,
+		},
+		{
+			name:        "no workers",
+			options:     LimitOptions{allNamespaces: true},
+			expectedErr: "--concurrency must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.validateFanOut()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
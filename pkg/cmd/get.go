@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	getExample = `
+    # List the LimitRanges of the current namespace with their values
+    kubectl lr get
+
+    # Show a single LimitRange
+    kubectl lr get my-limitrange --namespace=my-namespace
+
+    # List the LimitRanges of every namespace that carry a label
+    kubectl lr list --all-namespaces -l team=payments
+
+    # Print the LimitRanges as YAML
+    kubectl lr get -o yaml
+
+    # Print the names and max CPU of the LimitRanges
+    kubectl lr get -o custom-columns=NAME:.metadata.name,MAX-CPU:.spec.limits[*].max.cpu
+
+    # Print the names of the LimitRanges, for use in scripts
+    kubectl lr get -o name
+    `
+)
+
+// GetOptions holds information required to list LimitRanges
+type GetOptions struct {
+	configFlags   *genericclioptions.ConfigFlags
+	namespace     string
+	names         []string
+	allNamespaces bool
+	selector      string
+	output        string
+	printFlags    *genericclioptions.PrintFlags
+	IOStreams     genericclioptions.IOStreams
+	clientsetFunc func(config *rest.Config) (kubernetes.Interface, error)
+}
+
+// NewGetOptions initializes an instance of GetOptions with default values
+func NewGetOptions(streams genericclioptions.IOStreams) *GetOptions {
+	return &GetOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		printFlags:  newPrintFlags(),
+		IOStreams:   streams,
+		clientsetFunc: func(config *rest.Config) (kubernetes.Interface, error) {
+			return kubernetes.NewForConfig(config)
+		},
+	}
+}
+
+// NewCmdGet creates a cobra command listing LimitRanges and their values
+func NewCmdGet(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewGetOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "get [NAME...] [flags]",
+		Aliases:      []string{"list"},
+		Short:        "List LimitRanges with their values",
+		Example:      getExample,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Validate(); err != nil {
+				return fmt.Errorf("validation error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.configFlags.AddFlags(cmd.Flags())
+	if nsFlag := cmd.Flag("namespace"); nsFlag != nil {
+		nsFlag.Shorthand = "n"
+	}
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If true, list the LimitRanges of every namespace")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector to filter on, supports '=', '==', '!=', 'in' and 'notin'")
+	addOutputFlags(cmd, o.printFlags, &o.output, "wide", "custom-columns")
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Complete sets the names to get and resolves the namespace
+func (o *GetOptions) Complete(_ *cobra.Command, args []string) error {
+	o.names = args
+	if o.allNamespaces {
+		o.namespace = metav1.NamespaceAll
+		return nil
+	}
+	var err error
+	o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to get current namespace: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that the flags can be combined
+func (o *GetOptions) Validate() error {
+	if len(o.names) > 0 && o.allNamespaces {
+		return fmt.Errorf("a LimitRange cannot be retrieved by name across all namespaces")
+	}
+	if len(o.names) > 0 && o.selector != "" {
+		return fmt.Errorf("names cannot be combined with --selector")
+	}
+	if o.output == "" || o.output == "wide" {
+		return nil
+	}
+	_, err := o.toPrinter()
+	return err
+}
+
+// toPrinter returns the printer for --output, which may also be custom columns
+func (o *GetOptions) toPrinter() (printers.ResourcePrinter, error) {
+	if strings.HasPrefix(o.output, customColumnsPrefix) {
+		return newCustomColumnsPrinter(strings.TrimPrefix(o.output, customColumnsPrefix))
+	}
+	printer, err := toPrinter(o.printFlags, o.output)
+	if genericclioptions.IsNoCompatiblePrinterError(err) {
+		printFlags := o.printFlags
+		if printFlags == nil {
+			printFlags = newPrintFlags()
+		}
+		return nil, genericclioptions.NoCompatiblePrinterError{
+			OutputFormat:   &o.output,
+			AllowedFormats: append(printFlags.AllowedFormats(), "wide", "custom-columns"),
+		}
+	}
+	return printer, err
+}
+
+// Run fetches the LimitRanges and prints them as a table or in the --output format
+func (o *GetOptions) Run() error {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	limitRanges, err := o.fetchLimitRanges(clientset)
+	if err != nil {
+		return err
+	}
+
+	if o.output != "" && o.output != "wide" {
+		printer, err := o.toPrinter()
+		if err != nil {
+			return err
+		}
+		for i := range limitRanges {
+			setLimitRangeTypeMeta(&limitRanges[i])
+		}
+		// A single name prints the object itself, like kubectl get does. The name
+		// printer does not take typed lists, so it is given one object at a time.
+		if len(o.names) == 1 || o.output == "name" {
+			for i := range limitRanges {
+				if err := printer.PrintObj(&limitRanges[i], o.IOStreams.Out); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return printer.PrintObj(&v1.LimitRangeList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+			Items:    limitRanges,
+		}, o.IOStreams.Out)
+	}
+
+	if len(limitRanges) == 0 {
+		if o.allNamespaces {
+			fmt.Fprintln(o.IOStreams.ErrOut, "No resources found")
+		} else {
+			fmt.Fprintf(o.IOStreams.ErrOut, "No resources found in %s namespace.\n", o.namespace)
+		}
+		return nil
+	}
+	return printLimitRangesTable(o.IOStreams, limitRanges, o.output == "wide")
+}
+
+// fetchLimitRanges returns the named LimitRanges, or every LimitRange matching the selector
+// sorted by namespace and name
+func (o *GetOptions) fetchLimitRanges(clientset kubernetes.Interface) ([]v1.LimitRange, error) {
+	if len(o.names) > 0 {
+		limitRanges := make([]v1.LimitRange, 0, len(o.names))
+		for _, name := range o.names {
+			limitRange, err := clientset.CoreV1().LimitRanges(o.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get LimitRange: %w", err)
+			}
+			limitRanges = append(limitRanges, *limitRange)
+		}
+		return limitRanges, nil
+	}
+
+	list, err := clientset.CoreV1().LimitRanges(o.namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: o.selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].Namespace != list.Items[j].Namespace {
+			return list.Items[i].Namespace < list.Items[j].Namespace
+		}
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, nil
+}
+
+// printLimitRangesTable prints one row per LimitRange resource, naming each LimitRange on its first row.
+// The wide table adds the age and labels of each LimitRange.
+func printLimitRangesTable(streams genericclioptions.IOStreams, limitRanges []v1.LimitRange, wide bool) error {
+	w := printers.GetNewTabWriter(streams.Out)
+	header := append([]string{"NAMESPACE", "NAME"}, limitTableHeader...)
+	if wide {
+		header = append(header, "AGE", "LABELS")
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, limitRange := range limitRanges {
+		var rows [][]string
+		for _, item := range limitRange.Spec.Limits {
+			rows = append(rows, limitItemRows(item)...)
+		}
+		if len(rows) == 0 {
+			rows = [][]string{{"-", "-", "-", "-", "-", "-", "-"}}
+		}
+		for i, row := range rows {
+			namespace, name, age, labelList := "", "", "", ""
+			if i == 0 {
+				namespace, name = limitRange.Namespace, limitRange.Name
+				age, labelList = translateTimestampSince(limitRange.CreationTimestamp), labelsCell(limitRange.Labels)
+			}
+			cells := append([]string{namespace, name}, row...)
+			if wide {
+				cells = append(cells, age, labelList)
+			}
+			fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+	}
+	return w.Flush()
+}
+
+// translateTimestampSince returns the age of timestamp the way kubectl get shows it
+func translateTimestampSince(timestamp metav1.Time) string {
+	if timestamp.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(timestamp.Time))
+}
+
+// labelsCell formats labels as sorted key=value pairs, or <none>
+func labelsCell(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	return k8slabels.Set(labels).String()
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newGetOptions(fakeClientset kubernetes.Interface) *GetOptions {
+	options := &GetOptions{
+		namespace: "default",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer), ErrOut: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func listedLimitRanges() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "cpu", Namespace: "default", Labels: map[string]string{"team": "a"}},
+			Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{
+				Type: v1.LimitTypeContainer,
+				Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("1Gi")},
+				Min:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+			}}},
+		},
+		&v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "default", Labels: map[string]string{"team": "b"}},
+			Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{
+				Type: v1.LimitTypePersistentVolumeClaim,
+				Max:  v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			}}},
+		},
+		&v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "other"},
+		},
+	)
+}
+
+func TestRunGetTable(t *testing.T) {
+	options := newGetOptions(listedLimitRanges())
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t,
+		"NAMESPACE   NAME      TYPE                    RESOURCE   MIN    MAX    DEFAULT-REQUEST   DEFAULT   RATIO\n"+
+			"default     cpu       Container               cpu        100m   2      -                 -         -\n"+
+			"                      Container               memory     -      1Gi    -                 -         -\n"+
+			"default     storage   PersistentVolumeClaim   storage    -      10Gi   -                 -         -\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunGetAllNamespacesWithSelector(t *testing.T) {
+	options := newGetOptions(listedLimitRanges())
+	options.allNamespaces = true
+	options.namespace = metav1.NamespaceAll
+	options.selector = "team=b"
+
+	assert.NoError(t, options.Run())
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "storage")
+	assert.NotContains(t, output, "cpu")
+	assert.NotContains(t, output, "empty")
+
+	options = newGetOptions(listedLimitRanges())
+	options.namespace = metav1.NamespaceAll
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "other       empty     -")
+}
+
+func TestRunGetNoResources(t *testing.T) {
+	options := newGetOptions(fake.NewSimpleClientset())
+
+	assert.NoError(t, options.Run())
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "No resources found in default namespace.\n", options.IOStreams.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestRunGetOutput(t *testing.T) {
+	options := newGetOptions(listedLimitRanges())
+	options.names = []string{"cpu"}
+	options.output = "yaml"
+
+	assert.NoError(t, options.Run())
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "kind: LimitRange\n")
+	assert.Contains(t, output, "name: cpu\n")
+
+	options = newGetOptions(listedLimitRanges())
+	options.output = "json"
+
+	assert.NoError(t, options.Run())
+	output = options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, `"kind": "List"`)
+	assert.Contains(t, output, `"kind": "LimitRange"`)
+	assert.Contains(t, output, `"name": "storage"`)
+}
+
+func TestRunGetWide(t *testing.T) {
+	options := newGetOptions(listedLimitRanges())
+	options.output = "wide"
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t,
+		"NAMESPACE   NAME      TYPE                    RESOURCE   MIN    MAX    DEFAULT-REQUEST   DEFAULT   RATIO   AGE         LABELS\n"+
+			"default     cpu       Container               cpu        100m   2      -                 -         -       <unknown>   team=a\n"+
+			"                      Container               memory     -      1Gi    -                 -         -                   \n"+
+			"default     storage   PersistentVolumeClaim   storage    -      10Gi   -                 -         -       <unknown>   team=b\n",
+		options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+func TestRunGetPrinterFormats(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{output: "name", expected: "limitrange/cpu\nlimitrange/storage\n"},
+		{output: "jsonpath={.items[*].metadata.name}", expected: "cpu storage"},
+		{output: "go-template={{range .items}}{{.metadata.labels.team}} {{end}}", expected: "a b "},
+		{
+			output:   "custom-columns=NAME:.metadata.name,MAX:.spec.limits[*].max.cpu",
+			expected: "NAME      MAX\ncpu       2\nstorage   <none>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			options := newGetOptions(listedLimitRanges())
+			options.output = tt.output
+
+			assert.NoError(t, options.Validate())
+			assert.NoError(t, options.Run())
+			assert.Equal(t, tt.expected, options.IOStreams.Out.(*bytes.Buffer).String())
+		})
+	}
+}
+
+func TestRunGetMissingName(t *testing.T) {
+	options := newGetOptions(listedLimitRanges())
+	options.names = []string{"missing"}
+
+	assert.EqualError(t, options.Run(), `failed to get LimitRange: limitranges "missing" not found`)
+}
+
+func TestGetValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     GetOptions
+		expectedErr string
+	}{
+		{
+			name:    "valid",
+			options: GetOptions{names: []string{"a"}, output: "json"},
+		},
+		{
+			name:        "name across all namespaces",
+			options:     GetOptions{names: []string{"a"}, allNamespaces: true},
+			expectedErr: "a LimitRange cannot be retrieved by name across all namespaces",
+		},
+		{
+			name:        "name with selector",
+			options:     GetOptions{names: []string{"a"}, selector: "team=a"},
+			expectedErr: "names cannot be combined with --selector",
+		},
+		{
+			name:    "unsupported output",
+			options: GetOptions{output: "xml"},
+			expectedErr: `unable to match a printer suitable for the output format "xml", allowed formats are: ` +
+				"custom-columns,go-template,go-template-file,json,jsonpath,jsonpath-as-json,jsonpath-file,name,template,templatefile,wide,yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,18 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -22,25 +21,157 @@ var (
     # Create a LimitRange with CPU and memory limits in the specified namespace
     kubectl create limitrange my-limitrange --namespace=my-namespace --max-cpu="1" --min-cpu=100m --default-cpu=500m --default-request-cpu=500m --max-memory=500Mi --min-memory=100Mi
 
+    # Create a LimitRange that gives containers without resources a default memory limit and request
+    kubectl create limitrange my-memory-defaults --namespace=my-namespace --default-memory=256Mi --default-request-memory=128Mi
+
     # Create a LimitRange with only CPU limits
     kubectl create limitrange my-cpu-limit --namespace=my-namespace --max-cpu="2" --min-cpu=500m --default-cpu=1 --default-request-cpu=500m --dry-run=client -o yaml
+
+    # Create a LimitRange that also caps the aggregate usage of each Pod
+    kubectl create limitrange my-pod-limit --namespace=my-namespace --max-cpu=1 --max-pod-cpu=4 --max-pod-memory=2Gi
+
+    # Create a LimitRange that bounds the ephemeral storage of containers and pods
+    kubectl create limitrange my-ephemeral-limit --namespace=my-namespace --default-ephemeral-storage=1Gi --max-ephemeral-storage=4Gi --max-pod-ephemeral-storage=10Gi
+
+    # Create a LimitRange for hugepages and device plugin resources using the generic flags
+    kubectl create limitrange my-device-limit --namespace=my-namespace --max=hugepages-2Mi=1Gi --max=example.com/foo=2 --max=pod:example.com/foo=4
+
+    # Create a LimitRange from the built-in "small" preset with a higher memory limit
+    kubectl create limitrange my-small-limit --namespace=my-namespace --preset=small --max-memory=2Gi
+
+    # List the available presets
+    kubectl lr presets
+
+    # Create the LimitRanges described in a spec file, overriding the maximum CPU of its containers
+    kubectl create limitrange -f limits.yaml --max-cpu=2
+
+    # Create a LimitRange read from stdin
+    cat limits.yaml | kubectl create limitrange -f -
+
+    # Create or update a LimitRange with server-side apply, so the command can be re-run
+    kubectl create limitrange my-limitrange --namespace=my-namespace --max-cpu=2 --server-side --field-manager=platform-pipeline
+
+    # Update the container limits of an existing LimitRange, keeping its other items
+    kubectl create limitrange my-limitrange --namespace=my-namespace --max-cpu=2 --overwrite
+
+    # Create a LimitRange that bounds the size of PersistentVolumeClaims
+    kubectl create limitrange my-storage-limit --namespace=my-namespace --min-pvc-storage=1Gi --max-pvc-storage=50Gi
+
+    # Create a LimitRange labelled by team and cost center, recording who created it and with which version
+    kubectl create limitrange my-limitrange --namespace=my-namespace --max-cpu=1 --label=team=payments --label=cost-center=cc-42 --annotation=owner=payments@example.com --provenance
+
+    # Create a LimitRange that can later be updated with kubectl apply
+    kubectl create limitrange my-limitrange --namespace=my-namespace --max-cpu=1 --save-config
+
+    # Create a LimitRange and print the UID and resourceVersion the server assigned
+    kubectl create limitrange my-limitrange --namespace=my-namespace --max-cpu=1 -o jsonpath='{.metadata.uid} {.metadata.resourceVersion}'
+
+    # Create a LimitRange that keeps container limits within 4x of their requests
+    kubectl create limitrange my-ratio-limit --namespace=my-namespace --ratio-cpu=4 --ratio-memory=2
+
+    # Create the same LimitRange in every team namespace
+    kubectl create limitrange team-limits --namespace-selector=tier=team --preset=small
+
+    # Create a LimitRange in all namespaces except the system ones
+    kubectl create limitrange team-limits --all-namespaces --exclude-namespace='kube-*' --max-cpu=2
     `
 )
 
+// limitField identifies which resource list of a LimitRangeItem a value belongs to
+type limitField string
+
+const (
+	limitFieldMax            limitField = "max"
+	limitFieldMin            limitField = "min"
+	limitFieldDefault        limitField = "default"
+	limitFieldDefaultRequest limitField = "defaultRequest"
+	limitFieldRatio          limitField = "maxLimitRequestRatio"
+)
+
+// limitTypes lists the supported LimitRangeItem types in the order they are emitted
+var limitTypes = []v1.LimitType{v1.LimitTypeContainer, v1.LimitTypePod, v1.LimitTypePersistentVolumeClaim}
+
+// limitTypeNames maps the TYPE prefix accepted by the generic resource flags to a LimitType
+var limitTypeNames = map[string]v1.LimitType{
+	"container":             v1.LimitTypeContainer,
+	"pod":                   v1.LimitTypePod,
+	"pvc":                   v1.LimitTypePersistentVolumeClaim,
+	"persistentvolumeclaim": v1.LimitTypePersistentVolumeClaim,
+}
+
+// resourceFlag binds the value of a resource flag to the LimitRangeItem entry it populates
+type resourceFlag struct {
+	name      string
+	limitType v1.LimitType
+	field     limitField
+	resource  v1.ResourceName
+	value     string
+}
+
 // LimitOptions holds information required to create a LimitRange
 type LimitOptions struct {
-	configFlags       *genericclioptions.ConfigFlags
-	namespace         string
-	name              string
-	maxCPU            string
-	minCPU            string
-	defaultCPU        string
-	defaultRequestCPU string
-	maxMemory         string
-	minMemory         string
-	dryRun            string // Accepts "client" or "server"
-	output            string
-	IOStreams         genericclioptions.IOStreams
+	configFlags                    *genericclioptions.ConfigFlags
+	namespace                      string
+	namespaces                     []string
+	namespaceSelector              string
+	allNamespaces                  bool
+	excludeNamespaces              []string
+	concurrency                    int
+	name                           string
+	maxCPU                         string
+	minCPU                         string
+	defaultCPU                     string
+	defaultRequestCPU              string
+	maxMemory                      string
+	minMemory                      string
+	defaultMemory                  string
+	defaultRequestMemory           string
+	ratioCPU                       string
+	ratioMemory                    string
+	maxEphemeralStorage            string
+	minEphemeralStorage            string
+	defaultEphemeralStorage        string
+	defaultRequestEphemeralStorage string
+	maxPodCPU                      string
+	minPodCPU                      string
+	maxPodMemory                   string
+	minPodMemory                   string
+	ratioPodCPU                    string
+	ratioPodMemory                 string
+	maxPodEphemeralStorage         string
+	minPodEphemeralStorage         string
+	maxPVCStorage                  string
+	minPVCStorage                  string
+	ratioPVCStorage                string
+	maxResources                   []string
+	minResources                   []string
+	defaultResources               []string
+	defaultRequestResources        []string
+	ratioResources                 []string
+	filename                       string
+	presetName                     string
+	presetsFile                    string
+	serverSide                     bool
+	fieldManager                   string
+	forceConflicts                 bool
+	overwrite                      bool
+	labels                         []string
+	annotations                    []string
+	provenance                     bool
+	saveConfig                     bool
+	dryRun                         string // Accepts "client" or "server"
+	output                         string
+	printFlags                     *genericclioptions.PrintFlags
+	IOStreams                      genericclioptions.IOStreams
+
+	// specs holds the LimitRanges read from --filename, if any
+	specs []limitRangeSpec
+	// explicitNamespace is set when --namespace was given, so it overrides the spec file
+	explicitNamespace bool
+	// preset holds the values selected with --preset, if any
+	preset *limitRangePreset
+	// provenanceAnnotations holds the annotations computed by Complete for --provenance
+	provenanceAnnotations map[string]string
 
 	// Function to create Kubernetes clientset, can be overridden in tests
 	clientsetFunc func(config *rest.Config) (kubernetes.Interface, error)
@@ -51,8 +182,12 @@ type LimitOptions struct {
 //go:noinline
 func NewLimitOptions(streams genericclioptions.IOStreams) *LimitOptions {
 	return &LimitOptions{
-		configFlags: genericclioptions.NewConfigFlags(true),
-		IOStreams:   streams,
+		configFlags:  genericclioptions.NewConfigFlags(true),
+		presetsFile:  defaultPresetsFile(),
+		fieldManager: defaultFieldManager,
+		concurrency:  defaultConcurrency,
+		printFlags:   newPrintFlags(),
+		IOStreams:    streams,
 		clientsetFunc: func(config *rest.Config) (kubernetes.Interface, error) {
 			return kubernetes.NewForConfig(config)
 		},
@@ -64,13 +199,15 @@ func NewCmdCreateLimitRange(streams genericiooptions.IOStreams) *cobra.Command {
 	o := NewLimitOptions(streams)
 
 	cmd := &cobra.Command{
-		Use:          "limitrange NAME [flags]",
+		Use:          "limitrange NAME | -f FILENAME [flags]",
 		Short:        "Create a LimitRange resource",
 		Example:      limitExample,
 		SilenceUsage: true,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.MaximumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			o.name = args[0]
+			if len(args) > 0 {
+				o.name = args[0]
+			}
 			if err := o.Complete(c, args); err != nil {
 				return fmt.Errorf("completion error: %w", err)
 			}
@@ -84,6 +221,38 @@ func NewCmdCreateLimitRange(streams genericiooptions.IOStreams) *cobra.Command {
 		},
 	}
 
+	// coverage:ignore-start
+	// Cobra adds a completion command when it is called, which would take that NAME away.
+	// Shell completion keeps working through the hidden __complete command.
+	cmd.CompletionOptions.DisableDefaultCmd = true
+
+	// Replace the single --namespace of the config flags with one that accepts several namespaces
+	o.configFlags.Namespace = nil
+	o.addLimitFlags(cmd)
+
+	cmd.Flags().StringSliceVarP(&o.namespaces, "namespace", "n", nil, "Namespaces to create the LimitRange in, comma-separated or repeated. Defaults to the current namespace")
+	cmd.Flags().StringVar(&o.namespaceSelector, "namespace-selector", "", "Label selector of the namespaces to create the LimitRange in")
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If true, create the LimitRange in every namespace")
+	cmd.Flags().StringArrayVar(&o.excludeNamespaces, "exclude-namespace", nil, "Glob pattern of namespaces to skip, such as kube-*. Can be repeated.")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", o.concurrency, "Maximum number of namespaces to create the LimitRange in at the same time")
+	cmd.Flags().BoolVar(&o.serverSide, "server-side", false, "If true, use server-side apply instead of create, so re-running the command updates the LimitRange")
+	cmd.Flags().StringVar(&o.fieldManager, "field-manager", o.fieldManager, "Name of the manager used to track field ownership with --server-side")
+	cmd.Flags().BoolVar(&o.forceConflicts, "force-conflicts", false, "If true, server-side apply takes ownership of fields that conflict with other managers")
+	cmd.Flags().BoolVar(&o.overwrite, "overwrite", false, "If true, update an existing LimitRange of the same name, replacing its items of the given types")
+	cmd.Flags().StringArrayVar(&o.labels, "label", nil, "Label to set on the LimitRange as KEY=VALUE. Can be repeated.")
+	cmd.Flags().StringArrayVar(&o.annotations, "annotation", nil, "Annotation to set on the LimitRange as KEY=VALUE. Can be repeated.")
+	cmd.Flags().BoolVar(&o.provenance, "provenance", false, "If true, annotate the LimitRange with the plugin version, the kubeconfig user and the time it was generated")
+	cmd.Flags().BoolVar(&o.saveConfig, "save-config", false, "If true, the configuration of the LimitRange will be saved in its annotation, so it can later be managed with kubectl apply")
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", "", "Must be 'client' or 'server'. If set, only print the object that would be sent without sending it.")
+	addOutputFlags(cmd, o.printFlags, &o.output)
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// addLimitFlags adds the flags that describe the LimitRange, shared by every command
+// that builds one with createLimitRangeObject
+func (o *LimitOptions) addLimitFlags(cmd *cobra.Command) {
 	// coverage:ignore-start
 	// Add common flags
 	o.configFlags.AddFlags(cmd.Flags())
@@ -100,15 +269,50 @@ func NewCmdCreateLimitRange(streams genericiooptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVar(&o.defaultRequestCPU, "default-request-cpu", "", "Default CPU request for containers")
 	cmd.Flags().StringVar(&o.maxMemory, "max-memory", "", "Maximum memory limit for containers")
 	cmd.Flags().StringVar(&o.minMemory, "min-memory", "", "Minimum memory limit for containers")
-	cmd.Flags().StringVar(&o.dryRun, "dry-run", "", "Must be 'client' or 'server'. If set, only print the object that would be sent without sending it.")
-	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format. One of: yaml|json")
-
-	return cmd
+	cmd.Flags().StringVar(&o.defaultMemory, "default-memory", "", "Default memory limit for containers")
+	cmd.Flags().StringVar(&o.defaultRequestMemory, "default-request-memory", "", "Default memory request for containers")
+	cmd.Flags().StringVar(&o.ratioCPU, "ratio-cpu", "", "Maximum CPU limit to request ratio for containers")
+	cmd.Flags().StringVar(&o.ratioMemory, "ratio-memory", "", "Maximum memory limit to request ratio for containers")
+	cmd.Flags().StringVar(&o.maxEphemeralStorage, "max-ephemeral-storage", "", "Maximum ephemeral storage limit for containers")
+	cmd.Flags().StringVar(&o.minEphemeralStorage, "min-ephemeral-storage", "", "Minimum ephemeral storage limit for containers")
+	cmd.Flags().StringVar(&o.defaultEphemeralStorage, "default-ephemeral-storage", "", "Default ephemeral storage limit for containers")
+	cmd.Flags().StringVar(&o.defaultRequestEphemeralStorage, "default-request-ephemeral-storage", "", "Default ephemeral storage request for containers")
+
+	// Pod items cap the aggregate usage of all containers in a pod
+	cmd.Flags().StringVar(&o.maxPodCPU, "max-pod-cpu", "", "Maximum CPU limit for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.minPodCPU, "min-pod-cpu", "", "Minimum CPU request for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.maxPodMemory, "max-pod-memory", "", "Maximum memory limit for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.minPodMemory, "min-pod-memory", "", "Minimum memory request for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.ratioPodCPU, "ratio-pod-cpu", "", "Maximum CPU limit to request ratio for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.ratioPodMemory, "ratio-pod-memory", "", "Maximum memory limit to request ratio for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.maxPodEphemeralStorage, "max-pod-ephemeral-storage", "", "Maximum ephemeral storage limit for the sum of all containers in a pod")
+	cmd.Flags().StringVar(&o.minPodEphemeralStorage, "min-pod-ephemeral-storage", "", "Minimum ephemeral storage request for the sum of all containers in a pod")
+
+	// PersistentVolumeClaim items bound the storage a claim may request
+	cmd.Flags().StringVar(&o.maxPVCStorage, "max-pvc-storage", "", "Maximum storage request for PersistentVolumeClaims")
+	cmd.Flags().StringVar(&o.minPVCStorage, "min-pvc-storage", "", "Minimum storage request for PersistentVolumeClaims")
+	cmd.Flags().StringVar(&o.ratioPVCStorage, "ratio-pvc-storage", "", "Maximum storage limit to request ratio for PersistentVolumeClaims")
+
+	// Generic flags accept any resource name, e.g. hugepages-2Mi or extended resources
+	genericUsage := "as [TYPE:]RESOURCE=QUANTITY where TYPE is container (default), pod or pvc. Can be repeated."
+	cmd.Flags().StringArrayVar(&o.maxResources, "max", nil, "Maximum limit for any resource, "+genericUsage)
+	cmd.Flags().StringArrayVar(&o.minResources, "min", nil, "Minimum limit for any resource, "+genericUsage)
+	cmd.Flags().StringArrayVar(&o.defaultResources, "default", nil, "Default limit for any resource, "+genericUsage)
+	cmd.Flags().StringArrayVar(&o.defaultRequestResources, "default-request", nil, "Default request for any resource, "+genericUsage)
+	cmd.Flags().StringArrayVar(&o.ratioResources, "ratio", nil, "Maximum limit to request ratio for any resource, "+genericUsage)
+
+	cmd.Flags().StringVarP(&o.filename, "filename", "f", "", "YAML or JSON file describing one or more LimitRanges, or - to read from stdin. Resource flags override its values.")
+	cmd.Flags().StringVar(&o.presetName, "preset", "", "Name of a preset to start from. Resource flags override its values. See 'kubectl lr presets' for the available presets.")
+	cmd.Flags().StringVar(&o.presetsFile, "presets-file", o.presetsFile, "File holding user-defined presets")
 	// coverage:ignore-end
 }
 
 // Complete sets all required information for creating a LimitRange
 func (o *LimitOptions) Complete(_ *cobra.Command, _ []string) error {
+	o.explicitNamespace = len(o.namespaces) > 0 || (o.configFlags.Namespace != nil && *o.configFlags.Namespace != "")
+	if len(o.namespaces) == 1 {
+		o.namespace = o.namespaces[0]
+	}
 	if o.namespace == "" {
 		var err error
 		o.namespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
@@ -116,6 +320,26 @@ func (o *LimitOptions) Complete(_ *cobra.Command, _ []string) error {
 			return fmt.Errorf("failed to get current namespace: %w", err)
 		}
 	}
+	if o.filename != "" {
+		if err := o.loadSpecFile(); err != nil {
+			return err
+		}
+	}
+	if o.presetName != "" {
+		presets, err := loadPresets(o.presetsFile)
+		if err != nil {
+			return err
+		}
+		if o.preset, err = findPreset(presets, o.presetName); err != nil {
+			return err
+		}
+	}
+	if o.provenance {
+		var err error
+		if o.provenanceAnnotations, err = o.provenanceStamp(time.Now()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -124,82 +348,214 @@ func (o *LimitOptions) Validate() error {
 	if o.namespace == "" {
 		return fmt.Errorf("namespace cannot be empty")
 	}
-	if o.name == "" {
+	if o.name == "" && len(o.specs) == 0 {
 		return fmt.Errorf("name is required")
 	}
-	if o.maxMemory == "" && o.minMemory == "" && o.maxCPU == "" && o.minCPU == "" && o.defaultCPU == "" && o.defaultRequestCPU == "" {
+	if o.preset != nil && len(o.specs) > 0 {
+		return fmt.Errorf("--preset cannot be combined with --filename")
+	}
+	if o.forceConflicts && !o.serverSide {
+		return fmt.Errorf("--force-conflicts can only be used with --server-side")
+	}
+	if o.overwrite && o.serverSide {
+		return fmt.Errorf("--overwrite cannot be combined with --server-side")
+	}
+	if o.serverSide && o.fieldManager == "" {
+		return fmt.Errorf("--field-manager cannot be empty with --server-side")
+	}
+	if o.saveConfig && o.serverSide {
+		// Server-side apply tracks ownership in managedFields instead of the annotation
+		return fmt.Errorf("--save-config cannot be combined with --server-side")
+	}
+	if o.name != "" && len(o.specs) > 1 {
+		return fmt.Errorf("name cannot be given when the file describes %d LimitRanges", len(o.specs))
+	}
+	if err := o.validateFanOut(); err != nil {
+		return err
+	}
+	if _, err := toPrinter(o.printFlags, o.output); err != nil {
+		return err
+	}
+	if err := o.validateMetadata(); err != nil {
+		return err
+	}
+
+	if _, err := o.genericResourceFlags(); err != nil {
+		return err
+	}
+
+	flags := o.resourceFlags()
+
+	specified := len(o.specs) > 0 || o.preset != nil
+	for _, f := range flags {
+		if f.value != "" {
+			specified = true
+			break
+		}
+	}
+	if !specified {
 		return fmt.Errorf("at least one resource limit or request must be specified")
 	}
 
-	resourceFields := map[string]string{
-		"max-cpu":             o.maxCPU,
-		"min-cpu":             o.minCPU,
-		"default-cpu":         o.defaultCPU,
-		"default-request-cpu": o.defaultRequestCPU,
-		"max-memory":          o.maxMemory,
-		"min-memory":          o.minMemory,
+	setBy := map[string]string{}
+	for _, f := range flags {
+		if f.value == "" {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", f.limitType, f.field, f.resource)
+		if other, ok := setBy[key]; ok {
+			return fmt.Errorf("--%s conflicts with --%s: both set the same value", f.name, other)
+		}
+		setBy[key] = f.name
 	}
 
-	for fieldName, value := range resourceFields {
-		if value != "" {
-			quantity, err := resource.ParseQuantity(value)
-			if err != nil {
-				return fmt.Errorf("invalid %s value: %s", fieldName, err)
+	for _, f := range flags {
+		if f.value != "" {
+			if _, err := resource.ParseQuantity(f.value); err != nil {
+				return fmt.Errorf("invalid %s value: %s", f.name, err)
 			}
-			if quantity.Sign() != 1 {
-				// Sign() returns -1 for negative, 0 for zero, 1 for positive
-				return fmt.Errorf("invalid %s value: must be greater than zero", fieldName)
+		}
+	}
+
+	for _, limitRange := range o.limitRangeObjects() {
+		if err := o.validateLimitRange(limitRange); err != nil {
+			if len(o.specs) > 0 {
+				return fmt.Errorf("limitrange %q: %w", limitRange.Name, err)
 			}
+			return err
+		}
+		if o.saveConfig {
+			if _, err := lastAppliedConfiguration(limitRange); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateLimitRange checks the values of a LimitRange built from flags and spec file
+func (o *LimitOptions) validateLimitRange(limitRange *v1.LimitRange) error {
+	return validateLimitRangeValues(limitRange, func(index int) valueNamer {
+		return o.valueNamer(limitRange, index)
+	})
+}
+
+// validateLimitRangeValues checks the name and items of limitRange, naming the values of the
+// item at index in errors with namerFor(index)
+func validateLimitRangeValues(limitRange *v1.LimitRange, namerFor func(index int) valueNamer) error {
+	if limitRange.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(limitRange.Spec.Limits) == 0 {
+		return fmt.Errorf("at least one resource limit or request must be specified")
+	}
+	for i, item := range limitRange.Spec.Limits {
+		nameOf := namerFor(i)
+		if err := validateQuantities(item, nameOf); err != nil {
+			return err
+		}
+		if err := validateLimitRangeItem(item); err != nil {
+			return err
+		}
+		if err := validateLimitOrdering(item, nameOf); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// Run executes the creation of the LimitRange or prints the YAML/JSON
+// Run executes the creation of the LimitRange and prints the result, or only prints it on client-side dry-run
 func (o *LimitOptions) Run() error {
 	// Add validation
 	if err := o.Validate(); err != nil {
 		return err
 	}
 
-	limitRange := o.createLimitRangeObject()
+	if o.dryRun != "" && o.dryRun != "client" && o.dryRun != "server" {
+		return fmt.Errorf("invalid value for --dry-run: %s, must be 'client' or 'server'", o.dryRun)
+	}
+
+	// Client-side dry-run only needs the cluster to find the namespaces to fan out to
+	var clientset kubernetes.Interface
+	if o.dryRun != "client" || o.namespaceSelector != "" || o.allNamespaces {
+		config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+		}
+
+		clientset, err = o.clientsetFunc(config)
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+		}
+	}
+
+	if o.fanOut() {
+		return o.runFanOut(clientset)
+	}
+	return o.sendLimitRanges(clientset)
+}
+
+// sendLimitRanges creates the LimitRanges in their namespaces, or prints them on client-side dry-run
+func (o *LimitOptions) sendLimitRanges(clientset kubernetes.Interface) error {
+	limitRanges := o.limitRangeObjects()
 
 	// Handle client-side dry-run
 	if o.dryRun == "client" {
-		return o.printOutputWithTypeMeta(limitRange)
-	} else if o.dryRun != "" && o.dryRun != "server" {
-		return fmt.Errorf("invalid value for --dry-run: %s, must be 'client' or 'server'", o.dryRun)
+		operation := "created"
+		if o.serverSide {
+			operation = "serverside-applied"
+		} else if o.overwrite {
+			operation = "configured"
+		}
+		for i, limitRange := range limitRanges {
+			if i > 0 && o.output == "yaml" {
+				fmt.Fprintln(o.IOStreams.Out, "---")
+			}
+			if err := o.printOutputWithTypeMeta(limitRange, operation); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
+	for i, limitRange := range limitRanges {
+		if i > 0 && o.output == "yaml" {
+			fmt.Fprintln(o.IOStreams.Out, "---")
+		}
+		send := o.createLimitRange
+		if o.serverSide {
+			send = o.applyLimitRange
+		} else if o.overwrite {
+			send = o.overwriteLimitRange
+		}
+		if err := send(clientset, limitRange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createLimitRange sends a single LimitRange to the server and prints the result
+func (o *LimitOptions) createLimitRange(clientset kubernetes.Interface, limitRange *v1.LimitRange) error {
 	// Set CreateOptions for server-side dry-run
 	createOptions := metav1.CreateOptions{}
 	if o.dryRun == "server" {
 		createOptions.DryRun = []string{"All"}
 	}
 
-	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
-	if err != nil {
-		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
-	}
-
-	clientset, err := o.clientsetFunc(config)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
-	}
-
 	// Execute the create operation with the given options
-	createdLimitRange, err := clientset.CoreV1().LimitRanges(o.namespace).Create(context.TODO(), limitRange, createOptions)
+	createdLimitRange, err := clientset.CoreV1().LimitRanges(limitRange.Namespace).Create(context.TODO(), limitRange, createOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create LimitRange: %w", err)
 	}
 
-	// Print server response for server-side dry-run
-	if o.dryRun == "server" {
+	// Print the server response for server-side dry-run, or when an output format is requested
+	if o.dryRun == "server" || o.output != "" {
 		if createdLimitRange == nil {
 			// Use the original limitRange if createdLimitRange is nil
 			createdLimitRange = limitRange
 		}
-		return o.printOutputWithTypeMeta(createdLimitRange)
+		return o.printOutputWithTypeMeta(createdLimitRange, "created")
 	}
 
 	// Print success message
@@ -207,6 +563,92 @@ func (o *LimitOptions) Run() error {
 	return nil
 }
 
+// resourceFlags returns every resource flag along with the LimitRangeItem entry it maps to.
+// Entries of the generic flags that cannot be parsed are left out; Validate reports them.
+func (o *LimitOptions) resourceFlags() []resourceFlag {
+	flags := []resourceFlag{
+		{"max-cpu", v1.LimitTypeContainer, limitFieldMax, v1.ResourceCPU, o.maxCPU},
+		{"min-cpu", v1.LimitTypeContainer, limitFieldMin, v1.ResourceCPU, o.minCPU},
+		{"default-cpu", v1.LimitTypeContainer, limitFieldDefault, v1.ResourceCPU, o.defaultCPU},
+		{"default-request-cpu", v1.LimitTypeContainer, limitFieldDefaultRequest, v1.ResourceCPU, o.defaultRequestCPU},
+		{"max-memory", v1.LimitTypeContainer, limitFieldMax, v1.ResourceMemory, o.maxMemory},
+		{"min-memory", v1.LimitTypeContainer, limitFieldMin, v1.ResourceMemory, o.minMemory},
+		{"default-memory", v1.LimitTypeContainer, limitFieldDefault, v1.ResourceMemory, o.defaultMemory},
+		{"default-request-memory", v1.LimitTypeContainer, limitFieldDefaultRequest, v1.ResourceMemory, o.defaultRequestMemory},
+		{"ratio-cpu", v1.LimitTypeContainer, limitFieldRatio, v1.ResourceCPU, o.ratioCPU},
+		{"ratio-memory", v1.LimitTypeContainer, limitFieldRatio, v1.ResourceMemory, o.ratioMemory},
+		{"max-ephemeral-storage", v1.LimitTypeContainer, limitFieldMax, v1.ResourceEphemeralStorage, o.maxEphemeralStorage},
+		{"min-ephemeral-storage", v1.LimitTypeContainer, limitFieldMin, v1.ResourceEphemeralStorage, o.minEphemeralStorage},
+		{"default-ephemeral-storage", v1.LimitTypeContainer, limitFieldDefault, v1.ResourceEphemeralStorage, o.defaultEphemeralStorage},
+		{"default-request-ephemeral-storage", v1.LimitTypeContainer, limitFieldDefaultRequest, v1.ResourceEphemeralStorage, o.defaultRequestEphemeralStorage},
+		{"max-pod-cpu", v1.LimitTypePod, limitFieldMax, v1.ResourceCPU, o.maxPodCPU},
+		{"min-pod-cpu", v1.LimitTypePod, limitFieldMin, v1.ResourceCPU, o.minPodCPU},
+		{"max-pod-memory", v1.LimitTypePod, limitFieldMax, v1.ResourceMemory, o.maxPodMemory},
+		{"min-pod-memory", v1.LimitTypePod, limitFieldMin, v1.ResourceMemory, o.minPodMemory},
+		{"ratio-pod-cpu", v1.LimitTypePod, limitFieldRatio, v1.ResourceCPU, o.ratioPodCPU},
+		{"ratio-pod-memory", v1.LimitTypePod, limitFieldRatio, v1.ResourceMemory, o.ratioPodMemory},
+		{"max-pod-ephemeral-storage", v1.LimitTypePod, limitFieldMax, v1.ResourceEphemeralStorage, o.maxPodEphemeralStorage},
+		{"min-pod-ephemeral-storage", v1.LimitTypePod, limitFieldMin, v1.ResourceEphemeralStorage, o.minPodEphemeralStorage},
+		{"max-pvc-storage", v1.LimitTypePersistentVolumeClaim, limitFieldMax, v1.ResourceStorage, o.maxPVCStorage},
+		{"min-pvc-storage", v1.LimitTypePersistentVolumeClaim, limitFieldMin, v1.ResourceStorage, o.minPVCStorage},
+		{"ratio-pvc-storage", v1.LimitTypePersistentVolumeClaim, limitFieldRatio, v1.ResourceStorage, o.ratioPVCStorage},
+	}
+
+	generic, _ := o.genericResourceFlags()
+	return append(flags, generic...)
+}
+
+// genericResourceFlags parses the [TYPE:]RESOURCE=QUANTITY entries of the generic resource flags
+func (o *LimitOptions) genericResourceFlags() ([]resourceFlag, error) {
+	var flags []resourceFlag
+	for _, generic := range []struct {
+		name    string
+		field   limitField
+		entries []string
+	}{
+		{"max", limitFieldMax, o.maxResources},
+		{"min", limitFieldMin, o.minResources},
+		{"default", limitFieldDefault, o.defaultResources},
+		{"default-request", limitFieldDefaultRequest, o.defaultRequestResources},
+		{"ratio", limitFieldRatio, o.ratioResources},
+	} {
+		for _, entry := range generic.entries {
+			f, err := parseResourceFlag(generic.name, generic.field, entry)
+			if err != nil {
+				return nil, err
+			}
+			flags = append(flags, f)
+		}
+	}
+	return flags, nil
+}
+
+// parseResourceFlag parses a single [TYPE:]RESOURCE=QUANTITY entry of the generic flag named flagName
+func parseResourceFlag(flagName string, field limitField, entry string) (resourceFlag, error) {
+	key, value, ok := strings.Cut(entry, "=")
+	if !ok || key == "" || value == "" {
+		return resourceFlag{}, fmt.Errorf("invalid --%s value %q: must be [TYPE:]RESOURCE=QUANTITY", flagName, entry)
+	}
+
+	limitType := v1.LimitTypeContainer
+	resourceName := key
+	// Qualified names cannot contain a colon, so it unambiguously separates the type
+	if typeName, rest, found := strings.Cut(key, ":"); found {
+		t, known := limitTypeNames[strings.ToLower(typeName)]
+		if !known {
+			return resourceFlag{}, fmt.Errorf("invalid --%s value %q: unknown type %q, must be one of container, pod or pvc", flagName, entry, typeName)
+		}
+		limitType = t
+		resourceName = rest
+	}
+	if err := validateResourceName(limitType, v1.ResourceName(resourceName)); err != nil {
+		return resourceFlag{}, fmt.Errorf("invalid --%s value %q: %w", flagName, entry, err)
+	}
+
+	name := genericFlagName(limitType, field, v1.ResourceName(resourceName))
+	return resourceFlag{name, limitType, field, v1.ResourceName(resourceName), value}, nil
+}
+
 // createLimitRangeObject creates a new LimitRange object populated with provided options
 func (o *LimitOptions) createLimitRangeObject() *v1.LimitRange {
 	limitRange := &v1.LimitRange{
@@ -218,68 +660,175 @@ func (o *LimitOptions) createLimitRangeObject() *v1.LimitRange {
 			Name:      o.name,
 			Namespace: o.namespace,
 		},
-		Spec: v1.LimitRangeSpec{
-			Limits: []v1.LimitRangeItem{
-				{
-					Type:           v1.LimitTypeContainer,
-					Max:            v1.ResourceList{},
-					Min:            v1.ResourceList{},
-					Default:        v1.ResourceList{},
-					DefaultRequest: v1.ResourceList{},
-				},
-			},
-		},
 	}
 
-	// Populate resource values if they are provided
-	if o.maxCPU != "" {
-		limitRange.Spec.Limits[0].Max[v1.ResourceCPU] = resource.MustParse(o.maxCPU)
+	// Start from the preset, if any, so the flags override its values
+	if o.preset != nil {
+		for _, item := range o.preset.Limits {
+			limitRange.Spec.Limits = append(limitRange.Spec.Limits, *item.DeepCopy())
+		}
 	}
-	if o.minCPU != "" {
-		limitRange.Spec.Limits[0].Min[v1.ResourceCPU] = resource.MustParse(o.minCPU)
+
+	o.applyResourceFlags(limitRange)
+	o.applyMetadata(limitRange)
+	return limitRange
+}
+
+// limitRangeObjects returns the LimitRanges to create: one per spec read from --filename
+// with the resource flags applied on top, or the single object built from flags alone
+func (o *LimitOptions) limitRangeObjects() []*v1.LimitRange {
+	if len(o.specs) == 0 {
+		return []*v1.LimitRange{o.createLimitRangeObject()}
 	}
-	if o.defaultCPU != "" {
-		limitRange.Spec.Limits[0].Default[v1.ResourceCPU] = resource.MustParse(o.defaultCPU)
+
+	limitRanges := make([]*v1.LimitRange, 0, len(o.specs))
+	for _, spec := range o.specs {
+		limitRange := spec.toLimitRange()
+		if o.name != "" {
+			limitRange.Name = o.name
+		}
+		if limitRange.Namespace == "" || o.explicitNamespace {
+			limitRange.Namespace = o.namespace
+		}
+		o.applyResourceFlags(limitRange)
+		o.applyMetadata(limitRange)
+		limitRanges = append(limitRanges, limitRange)
 	}
-	if o.defaultRequestCPU != "" {
-		limitRange.Spec.Limits[0].DefaultRequest[v1.ResourceCPU] = resource.MustParse(o.defaultRequestCPU)
+	return limitRanges
+}
+
+// applyResourceFlags sets every resource flag value on limitRange, overriding existing values
+// and appending an item for each limit type that is not yet present
+func (o *LimitOptions) applyResourceFlags(limitRange *v1.LimitRange) {
+	flags := o.resourceFlags()
+	for _, limitType := range limitTypes {
+		var item *v1.LimitRangeItem
+		for _, f := range flags {
+			if f.value == "" || f.limitType != limitType {
+				continue
+			}
+			if item == nil {
+				item = limitRangeItemFor(limitRange, limitType)
+			}
+			resourceListFor(item, f.field)[f.resource] = resource.MustParse(f.value)
+		}
 	}
-	if o.maxMemory != "" {
-		limitRange.Spec.Limits[0].Max[v1.ResourceMemory] = resource.MustParse(o.maxMemory)
+}
+
+// limitRangeItemFor returns the item of limitType in limitRange, appending an empty one if needed
+func limitRangeItemFor(limitRange *v1.LimitRange, limitType v1.LimitType) *v1.LimitRangeItem {
+	index := -1
+	for i := range limitRange.Spec.Limits {
+		if limitRange.Spec.Limits[i].Type == limitType {
+			index = i
+			break
+		}
 	}
-	if o.minMemory != "" {
-		limitRange.Spec.Limits[0].Min[v1.ResourceMemory] = resource.MustParse(o.minMemory)
+	if index < 0 {
+		limitRange.Spec.Limits = append(limitRange.Spec.Limits, v1.LimitRangeItem{Type: limitType})
+		index = len(limitRange.Spec.Limits) - 1
 	}
 
-	return limitRange
+	item := &limitRange.Spec.Limits[index]
+	if item.Max == nil {
+		item.Max = v1.ResourceList{}
+	}
+	if item.Min == nil {
+		item.Min = v1.ResourceList{}
+	}
+	if item.Default == nil {
+		item.Default = v1.ResourceList{}
+	}
+	if item.DefaultRequest == nil {
+		item.DefaultRequest = v1.ResourceList{}
+	}
+	if item.MaxLimitRequestRatio == nil {
+		item.MaxLimitRequestRatio = v1.ResourceList{}
+	}
+	return item
 }
 
-// printOutputWithTypeMeta ensures TypeMeta is set and prints the LimitRange in the specified format
-func (o *LimitOptions) printOutputWithTypeMeta(limitRange *v1.LimitRange) error {
-	// Ensure TypeMeta is set
-	if limitRange.TypeMeta.APIVersion == "" || limitRange.TypeMeta.Kind == "" {
-		limitRange.TypeMeta = metav1.TypeMeta{
-			APIVersion: "v1",
-			Kind:       "LimitRange",
+// valueNamer names the values of the item at index of limitRange after the flag that set them.
+// Values that come from a spec file or preset are named after their field, such as limits[1].max.memory.
+func (o *LimitOptions) valueNamer(limitRange *v1.LimitRange, index int) valueNamer {
+	item := limitRange.Spec.Limits[index]
+	// Flags only set values on the first item of their type, see limitRangeItemFor
+	first := true
+	for _, other := range limitRange.Spec.Limits[:index] {
+		if other.Type == item.Type {
+			first = false
 		}
 	}
+	flags := o.resourceFlags()
+	return func(field limitField, name v1.ResourceName) (string, bool) {
+		for _, f := range flags {
+			if first && f.value != "" && f.limitType == item.Type && f.field == field && f.resource == name {
+				return f.name, true
+			}
+		}
+		if strings.ContainsAny(string(name), "./") {
+			// Keep names such as example.com/foo apart from the path
+			return fmt.Sprintf("limits[%d].%s[%s]", index, field, name), false
+		}
+		return fmt.Sprintf("limits[%d].%s.%s", index, field, name), false
+	}
+}
 
-	var output []byte
-	var err error
+// genericFlagName returns the generic flag form, e.g. max=pod:cpu, that sets the given entry
+func genericFlagName(limitType v1.LimitType, field limitField, name v1.ResourceName) string {
+	flagName := map[limitField]string{
+		limitFieldMax:            "max",
+		limitFieldMin:            "min",
+		limitFieldDefault:        "default",
+		limitFieldDefaultRequest: "default-request",
+		limitFieldRatio:          "ratio",
+	}[field]
+	switch limitType {
+	case v1.LimitTypeContainer:
+		return fmt.Sprintf("%s=%s", flagName, name)
+	case v1.LimitTypePersistentVolumeClaim:
+		return fmt.Sprintf("%s=pvc:%s", flagName, name)
+	default:
+		return fmt.Sprintf("%s=%s:%s", flagName, strings.ToLower(string(limitType)), name)
+	}
+}
 
-	if o.output == "yaml" {
-		output, err = yaml.Marshal(limitRange)
-	} else if o.output == "json" {
-		serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Pretty: true})
-		output, err = runtime.Encode(serializer, limitRange)
-	} else {
-		return fmt.Errorf("unsupported output format: %s", o.output)
+// resourceListFor returns the resource list of item that field refers to
+func resourceListFor(item *v1.LimitRangeItem, field limitField) v1.ResourceList {
+	switch field {
+	case limitFieldMin:
+		return item.Min
+	case limitFieldDefault:
+		return item.Default
+	case limitFieldDefaultRequest:
+		return item.DefaultRequest
+	case limitFieldRatio:
+		return item.MaxLimitRequestRatio
+	default:
+		return item.Max
 	}
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to format output: %w", err)
+// printOutputWithTypeMeta ensures TypeMeta is set and prints the LimitRange in the --output format.
+// Without one, the LimitRange is printed by name followed by operation and the dry-run mode,
+// e.g. limitrange/my-limitrange created (dry run).
+func (o *LimitOptions) printOutputWithTypeMeta(limitRange *v1.LimitRange, operation string) error {
+	setLimitRangeTypeMeta(limitRange)
+	switch o.dryRun {
+	case "client":
+		operation += " (dry run)"
+	case "server":
+		operation += " (server dry run)"
 	}
+	return printObject(o.IOStreams.Out, withOperation(o.printFlags, operation), o.output, limitRange)
+}
 
-	fmt.Fprintf(o.IOStreams.Out, "%s\n", output)
-	return nil
+// setLimitRangeTypeMeta sets the TypeMeta the API server leaves out of typed responses
+func setLimitRangeTypeMeta(limitRange *v1.LimitRange) {
+	if limitRange.TypeMeta.APIVersion == "" || limitRange.TypeMeta.Kind == "" {
+		limitRange.TypeMeta = metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "LimitRange",
+		}
+	}
 }
@@ -45,6 +45,289 @@ func TestCreateLimitRangeObject(t *testing.T) {
 	assert.Equal(t, "128Mi", (&minMemory).String())
 }
 
+func TestCreateLimitRangeObjectWithMemoryDefaults(t *testing.T) {
+	options := &LimitOptions{
+		name:                 "test-limitrange",
+		namespace:            "default",
+		defaultMemory:        "256Mi",
+		defaultRequestMemory: "128Mi",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 1) {
+		defaultMemory := limitRange.Spec.Limits[0].Default[v1.ResourceMemory]
+		defaultRequestMemory := limitRange.Spec.Limits[0].DefaultRequest[v1.ResourceMemory]
+		assert.Equal(t, "256Mi", (&defaultMemory).String())
+		assert.Equal(t, "128Mi", (&defaultRequestMemory).String())
+	}
+}
+
+func TestCreateLimitRangeObjectWithRatios(t *testing.T) {
+	options := &LimitOptions{
+		name:            "test-limitrange",
+		namespace:       "default",
+		ratioCPU:        "4",
+		ratioMemory:     "1.5",
+		ratioPodCPU:     "2",
+		ratioPVCStorage: "1",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 3) {
+		ratioCPU := limitRange.Spec.Limits[0].MaxLimitRequestRatio[v1.ResourceCPU]
+		ratioMemory := limitRange.Spec.Limits[0].MaxLimitRequestRatio[v1.ResourceMemory]
+		ratioPodCPU := limitRange.Spec.Limits[1].MaxLimitRequestRatio[v1.ResourceCPU]
+		ratioPVCStorage := limitRange.Spec.Limits[2].MaxLimitRequestRatio[v1.ResourceStorage]
+		assert.Equal(t, "4", (&ratioCPU).String())
+		assert.Equal(t, "1500m", (&ratioMemory).String())
+		assert.Equal(t, "2", (&ratioPodCPU).String())
+		assert.Equal(t, "1", (&ratioPVCStorage).String())
+	}
+}
+
+func TestCreateLimitRangeObjectWithEphemeralStorage(t *testing.T) {
+	options := &LimitOptions{
+		name:                           "test-limitrange",
+		namespace:                      "default",
+		maxEphemeralStorage:            "4Gi",
+		minEphemeralStorage:            "100Mi",
+		defaultEphemeralStorage:        "1Gi",
+		defaultRequestEphemeralStorage: "500Mi",
+		maxPodEphemeralStorage:         "10Gi",
+		minPodEphemeralStorage:         "200Mi",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 2) {
+		containerLimits := limitRange.Spec.Limits[0]
+		maxStorage := containerLimits.Max[v1.ResourceEphemeralStorage]
+		minStorage := containerLimits.Min[v1.ResourceEphemeralStorage]
+		defaultStorage := containerLimits.Default[v1.ResourceEphemeralStorage]
+		defaultRequestStorage := containerLimits.DefaultRequest[v1.ResourceEphemeralStorage]
+		assert.Equal(t, "4Gi", (&maxStorage).String())
+		assert.Equal(t, "100Mi", (&minStorage).String())
+		assert.Equal(t, "1Gi", (&defaultStorage).String())
+		assert.Equal(t, "500Mi", (&defaultRequestStorage).String())
+
+		podLimits := limitRange.Spec.Limits[1]
+		maxPodStorage := podLimits.Max[v1.ResourceEphemeralStorage]
+		minPodStorage := podLimits.Min[v1.ResourceEphemeralStorage]
+		assert.Equal(t, "10Gi", (&maxPodStorage).String())
+		assert.Equal(t, "200Mi", (&minPodStorage).String())
+	}
+}
+
+func TestCreateLimitRangeObjectWithGenericResources(t *testing.T) {
+	options := &LimitOptions{
+		name:                    "test-limitrange",
+		namespace:               "default",
+		maxCPU:                  "1",
+		maxResources:            []string{"hugepages-2Mi=1Gi", "example.com/foo=2", "pod:example.com/foo=4"},
+		defaultRequestResources: []string{"container:example.com/foo=2"},
+		ratioResources:          []string{"pvc:storage=2"},
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 3) {
+		containerLimits := limitRange.Spec.Limits[0]
+		maxCPU := containerLimits.Max[v1.ResourceCPU]
+		maxHugepages := containerLimits.Max["hugepages-2Mi"]
+		maxFoo := containerLimits.Max["example.com/foo"]
+		defaultRequestFoo := containerLimits.DefaultRequest["example.com/foo"]
+		assert.Equal(t, "1", (&maxCPU).String())
+		assert.Equal(t, "1Gi", (&maxHugepages).String())
+		assert.Equal(t, "2", (&maxFoo).String())
+		assert.Equal(t, "2", (&defaultRequestFoo).String())
+
+		maxPodFoo := limitRange.Spec.Limits[1].Max["example.com/foo"]
+		assert.Equal(t, v1.LimitTypePod, limitRange.Spec.Limits[1].Type)
+		assert.Equal(t, "4", (&maxPodFoo).String())
+
+		ratioStorage := limitRange.Spec.Limits[2].MaxLimitRequestRatio[v1.ResourceStorage]
+		assert.Equal(t, v1.LimitTypePersistentVolumeClaim, limitRange.Spec.Limits[2].Type)
+		assert.Equal(t, "2", (&ratioStorage).String())
+	}
+}
+
+func TestValueNamer(t *testing.T) {
+	options := &LimitOptions{maxCPU: "1", maxResources: []string{"example.com/foo=2"}}
+	limitRange := &v1.LimitRange{Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{
+		{Type: v1.LimitTypeContainer},
+		{Type: v1.LimitTypePod},
+		{Type: v1.LimitTypeContainer},
+	}}}
+
+	testCases := []struct {
+		index          int
+		field          limitField
+		resource       v1.ResourceName
+		expectedName   string
+		expectedIsFlag bool
+	}{
+		{0, limitFieldMax, v1.ResourceCPU, "max-cpu", true},
+		{0, limitFieldMax, "example.com/foo", "max=example.com/foo", true},
+		// Values no flag was given for come from the spec file or preset
+		{0, limitFieldMin, v1.ResourceCPU, "limits[0].min.cpu", false},
+		{1, limitFieldMax, v1.ResourceMemory, "limits[1].max.memory", false},
+		{1, limitFieldMax, "example.com/foo", "limits[1].max[example.com/foo]", false},
+		// Flags only set the first item of their type
+		{2, limitFieldMax, v1.ResourceCPU, "limits[2].max.cpu", false},
+	}
+	for _, tc := range testCases {
+		name, isFlag := options.valueNamer(limitRange, tc.index)(tc.field, tc.resource)
+		assert.Equal(t, tc.expectedName, name)
+		assert.Equal(t, tc.expectedIsFlag, isFlag)
+	}
+}
+
+func TestParseResourceFlag(t *testing.T) {
+	testCases := []struct {
+		entry         string
+		expected      resourceFlag
+		errorContains string
+	}{
+		{
+			entry:    "hugepages-1Gi=2Gi",
+			expected: resourceFlag{"max=hugepages-1Gi", v1.LimitTypeContainer, limitFieldMax, "hugepages-1Gi", "2Gi"},
+		},
+		{
+			entry:    "Pod:example.com/foo=4",
+			expected: resourceFlag{"max=pod:example.com/foo", v1.LimitTypePod, limitFieldMax, "example.com/foo", "4"},
+		},
+		{
+			entry:    "persistentvolumeclaim:storage=1Ti",
+			expected: resourceFlag{"max=pvc:storage", v1.LimitTypePersistentVolumeClaim, limitFieldMax, v1.ResourceStorage, "1Ti"},
+		},
+		{
+			entry:         "cpu",
+			errorContains: "must be [TYPE:]RESOURCE=QUANTITY",
+		},
+		{
+			entry:         "=1",
+			errorContains: "must be [TYPE:]RESOURCE=QUANTITY",
+		},
+		{
+			entry:         "node:cpu=1",
+			errorContains: "unknown type \"node\"",
+		},
+		{
+			entry:         "example.com/foo bar=1",
+			errorContains: "invalid resource name \"example.com/foo bar\"",
+		},
+		{
+			entry:         "-foo=1",
+			errorContains: "invalid resource name \"-foo\"",
+		},
+		{
+			entry:         "foo=1",
+			errorContains: "invalid resource name \"foo\": must be cpu, memory, ephemeral-storage, hugepages-<size> or a prefixed name such as example.com/foo",
+		},
+		{
+			entry:         "pod:requests.example.com/foo=1",
+			errorContains: "invalid resource name \"requests.example.com/foo\": does not follow the extended resource name standard",
+		},
+		{
+			entry:         "pvc:foo=1",
+			errorContains: "invalid resource name \"foo\": must be storage or a prefixed name such as example.com/foo",
+		},
+		{
+			entry:         "pvc:requests.example.com/foo=1",
+			errorContains: "invalid resource name \"requests.example.com/foo\": does not follow the extended resource name standard",
+		},
+		{
+			entry:    "pvc:example.com/foo=1",
+			expected: resourceFlag{"max=pvc:example.com/foo", v1.LimitTypePersistentVolumeClaim, limitFieldMax, "example.com/foo", "1"},
+		},
+		{
+			entry:    "kubernetes.io/foo=1",
+			expected: resourceFlag{"max=kubernetes.io/foo", v1.LimitTypeContainer, limitFieldMax, "kubernetes.io/foo", "1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.entry, func(t *testing.T) {
+			f, err := parseResourceFlag("max", limitFieldMax, tc.entry)
+			if tc.errorContains != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.errorContains)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
+
+func TestCreateLimitRangeObjectWithPodLimits(t *testing.T) {
+	options := &LimitOptions{
+		name:         "test-limitrange",
+		namespace:    "default",
+		maxCPU:       "1",
+		maxPodCPU:    "4",
+		minPodCPU:    "200m",
+		maxPodMemory: "2Gi",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 2) {
+		assert.Equal(t, v1.LimitTypeContainer, limitRange.Spec.Limits[0].Type)
+		assert.Equal(t, v1.LimitTypePod, limitRange.Spec.Limits[1].Type)
+
+		podLimits := limitRange.Spec.Limits[1]
+		maxPodCPU := podLimits.Max[v1.ResourceCPU]
+		minPodCPU := podLimits.Min[v1.ResourceCPU]
+		maxPodMemory := podLimits.Max[v1.ResourceMemory]
+		assert.Equal(t, "4", (&maxPodCPU).String())
+		assert.Equal(t, "200m", (&minPodCPU).String())
+		assert.Equal(t, "2Gi", (&maxPodMemory).String())
+		assert.Empty(t, podLimits.Default)
+		assert.Empty(t, podLimits.DefaultRequest)
+	}
+}
+
+func TestCreateLimitRangeObjectOnlyPodLimits(t *testing.T) {
+	options := &LimitOptions{
+		name:         "test-limitrange",
+		namespace:    "default",
+		maxPodMemory: "2Gi",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 1) {
+		assert.Equal(t, v1.LimitTypePod, limitRange.Spec.Limits[0].Type)
+	}
+}
+
+func TestCreateLimitRangeObjectWithPVCLimits(t *testing.T) {
+	options := &LimitOptions{
+		name:          "test-limitrange",
+		namespace:     "default",
+		maxCPU:        "1",
+		maxPodMemory:  "2Gi",
+		maxPVCStorage: "50Gi",
+		minPVCStorage: "1Gi",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 3) {
+		pvcLimits := limitRange.Spec.Limits[2]
+		assert.Equal(t, v1.LimitTypePersistentVolumeClaim, pvcLimits.Type)
+
+		maxStorage := pvcLimits.Max[v1.ResourceStorage]
+		minStorage := pvcLimits.Min[v1.ResourceStorage]
+		assert.Equal(t, "50Gi", (&maxStorage).String())
+		assert.Equal(t, "1Gi", (&minStorage).String())
+	}
+}
+
 func TestComplete(t *testing.T) {
 	options := &LimitOptions{
 		configFlags: genericclioptions.NewConfigFlags(true),
@@ -128,6 +411,229 @@ func TestValidateResourceQuantities(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "Valid memory defaults",
+			options: &LimitOptions{
+				namespace:            "default",
+				name:                 "test-limitrange",
+				defaultMemory:        "256Mi",
+				defaultRequestMemory: "128Mi",
+			},
+			expectError: false,
+		},
+		{
+			name: "Zero defaultMemory",
+			options: &LimitOptions{
+				namespace:     "default",
+				name:          "test-limitrange",
+				defaultMemory: "0",
+			},
+			expectError:   true,
+			errorContains: "invalid default-memory value: must be greater than zero",
+		},
+		{
+			name: "Unparsable defaultRequestMemory",
+			options: &LimitOptions{
+				namespace:            "default",
+				name:                 "test-limitrange",
+				defaultRequestMemory: "128 megs",
+			},
+			expectError:   true,
+			errorContains: "invalid default-request-memory value",
+		},
+		{
+			name: "Valid ratios",
+			options: &LimitOptions{
+				namespace:      "default",
+				name:           "test-limitrange",
+				ratioCPU:       "1",
+				ratioPodMemory: "2.5",
+			},
+			expectError: false,
+		},
+		{
+			name: "Ratio below one",
+			options: &LimitOptions{
+				namespace: "default",
+				name:      "test-limitrange",
+				ratioCPU:  "500m",
+			},
+			expectError:   true,
+			errorContains: "invalid ratio-cpu value: must be at least 1",
+		},
+		{
+			name: "Zero ratio",
+			options: &LimitOptions{
+				namespace:       "default",
+				name:            "test-limitrange",
+				ratioPVCStorage: "0",
+			},
+			expectError:   true,
+			errorContains: "invalid ratio-pvc-storage value: must be greater than zero",
+		},
+		{
+			name: "Valid ephemeral storage",
+			options: &LimitOptions{
+				namespace:               "default",
+				name:                    "test-limitrange",
+				defaultEphemeralStorage: "1Gi",
+				maxPodEphemeralStorage:  "10Gi",
+			},
+			expectError: false,
+		},
+		{
+			name: "Zero defaultRequestEphemeralStorage",
+			options: &LimitOptions{
+				namespace:                      "default",
+				name:                           "test-limitrange",
+				defaultRequestEphemeralStorage: "0",
+			},
+			expectError:   true,
+			errorContains: "invalid default-request-ephemeral-storage value: must be greater than zero",
+		},
+		{
+			name: "Negative minPodEphemeralStorage",
+			options: &LimitOptions{
+				namespace:              "default",
+				name:                   "test-limitrange",
+				minPodEphemeralStorage: "-1Gi",
+			},
+			expectError:   true,
+			errorContains: "invalid min-pod-ephemeral-storage value: must be greater than zero",
+		},
+		{
+			name: "Ephemeral storage default above max",
+			options: &LimitOptions{
+				namespace:               "default",
+				name:                    "test-limitrange",
+				defaultEphemeralStorage: "8Gi",
+				maxEphemeralStorage:     "4Gi",
+			},
+			expectError:   true,
+			errorContains: "--default-ephemeral-storage=8Gi must not be greater than --max-ephemeral-storage=4Gi",
+		},
+		{
+			name: "Valid generic resources",
+			options: &LimitOptions{
+				namespace:        "default",
+				name:             "test-limitrange",
+				maxResources:     []string{"hugepages-2Mi=1Gi", "pod:example.com/foo=4"},
+				defaultResources: []string{"hugepages-2Mi=512Mi"},
+			},
+			expectError: false,
+		},
+		{
+			name: "Invalid generic resource name",
+			options: &LimitOptions{
+				namespace:    "default",
+				name:         "test-limitrange",
+				minResources: []string{"example.com/bad name=1"},
+			},
+			expectError:   true,
+			errorContains: "invalid --min value \"example.com/bad name=1\": invalid resource name",
+		},
+		{
+			name: "Invalid generic quantity",
+			options: &LimitOptions{
+				namespace:    "default",
+				name:         "test-limitrange",
+				maxResources: []string{"pod:example.com/foo=many"},
+			},
+			expectError:   true,
+			errorContains: "invalid max=pod:example.com/foo value",
+		},
+		{
+			name: "Generic ratio below one",
+			options: &LimitOptions{
+				namespace:      "default",
+				name:           "test-limitrange",
+				ratioResources: []string{"example.com/foo=0.5"},
+			},
+			expectError:   true,
+			errorContains: "invalid ratio=example.com/foo value: must be at least 1",
+		},
+		{
+			name: "Generic flag conflicts with dedicated flag",
+			options: &LimitOptions{
+				namespace:    "default",
+				name:         "test-limitrange",
+				maxCPU:       "1",
+				maxResources: []string{"cpu=2"},
+			},
+			expectError:   true,
+			errorContains: "--max=cpu conflicts with --max-cpu",
+		},
+		{
+			name: "Generic default for pods",
+			options: &LimitOptions{
+				namespace:        "default",
+				name:             "test-limitrange",
+				defaultResources: []string{"pod:cpu=1"},
+			},
+			expectError:   true,
+			errorContains: "default may not be specified for Pod limits",
+		},
+		{
+			name: "Generic values out of order",
+			options: &LimitOptions{
+				namespace:    "default",
+				name:         "test-limitrange",
+				minResources: []string{"hugepages-2Mi=2Gi"},
+				maxResources: []string{"hugepages-2Mi=1Gi"},
+			},
+			expectError:   true,
+			errorContains: "--min=hugepages-2Mi=2Gi must not be greater than --max=hugepages-2Mi=1Gi",
+		},
+		{
+			name: "Valid pod limits only",
+			options: &LimitOptions{
+				namespace:    "default",
+				name:         "test-limitrange",
+				maxPodCPU:    "2",
+				minPodMemory: "64Mi",
+			},
+			expectError: false,
+		},
+		{
+			name: "Zero maxPodMemory",
+			options: &LimitOptions{
+				namespace:    "default",
+				name:         "test-limitrange",
+				maxPodMemory: "0",
+			},
+			expectError:   true,
+			errorContains: "invalid max-pod-memory value: must be greater than zero",
+		},
+		{
+			name: "Unparsable minPodCPU",
+			options: &LimitOptions{
+				namespace: "default",
+				name:      "test-limitrange",
+				minPodCPU: "lots",
+			},
+			expectError:   true,
+			errorContains: "invalid min-pod-cpu value",
+		},
+		{
+			name: "Valid pvc storage limits",
+			options: &LimitOptions{
+				namespace:     "default",
+				name:          "test-limitrange",
+				maxPVCStorage: "10Gi",
+				minPVCStorage: "1Gi",
+			},
+			expectError: false,
+		},
+		{
+			name: "Negative maxPVCStorage",
+			options: &LimitOptions{
+				namespace:     "default",
+				name:          "test-limitrange",
+				maxPVCStorage: "-10Gi",
+			},
+			expectError:   true,
+			errorContains: "invalid max-pvc-storage value: must be greater than zero",
+		},
 		{
 			name: "No resource limits specified",
 			options: &LimitOptions{
@@ -180,7 +686,7 @@ func TestPrintOutputWithTypeMeta(t *testing.T) {
 	}
 
 	limitRange := options.createLimitRangeObject()
-	err := options.printOutputWithTypeMeta(limitRange)
+	err := options.printOutputWithTypeMeta(limitRange, "created")
 	assert.NoError(t, err, "expected no error while printing output")
 
 	// Verify output
@@ -198,7 +704,7 @@ func TestPrintOutputWithTypeMetaJSON(t *testing.T) {
 	}
 
 	limitRange := options.createLimitRangeObject()
-	err := options.printOutputWithTypeMeta(limitRange)
+	err := options.printOutputWithTypeMeta(limitRange, "created")
 	assert.NoError(t, err, "expected no error while printing output")
 
 	// Verify output
@@ -207,6 +713,43 @@ func TestPrintOutputWithTypeMetaJSON(t *testing.T) {
 	assert.Contains(t, output, "\"kind\": \"LimitRange\"")
 }
 
+func TestPrintOutputWithTypeMetaFormats(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{output: "name", expected: "limitrange/test-limitrange\n"},
+		{output: "jsonpath={.kind} {.spec.limits[0].max.cpu}", expected: "LimitRange 1"},
+		{output: "go-template={{.metadata.namespace}}/{{.metadata.name}}", expected: "default/test-limitrange"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			options := &LimitOptions{
+				name:      "test-limitrange",
+				namespace: "default",
+				maxCPU:    "1",
+				output:    tt.output,
+				IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+			}
+
+			assert.NoError(t, options.printOutputWithTypeMeta(options.createLimitRangeObject(), "created"))
+			assert.Equal(t, tt.expected, options.IOStreams.Out.(*bytes.Buffer).String())
+		})
+	}
+}
+
+// fakeClientsetFlags returns config flags that resolve to an empty client config, so that no
+// kubeconfig is read, along with a clientsetFunc handing out fakeClientset
+func fakeClientsetFlags(fakeClientset kubernetes.Interface) (*genericclioptions.ConfigFlags, func(*rest.Config) (kubernetes.Interface, error)) {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	configFlags.WrapConfigFn = func(_ *rest.Config) *rest.Config {
+		return &rest.Config{}
+	}
+	return configFlags, func(_ *rest.Config) (kubernetes.Interface, error) {
+		return fakeClientset, nil
+	}
+}
+
 func TestRunWithFakeClient(t *testing.T) {
 	fakeClientset := fake.NewSimpleClientset()
 
@@ -236,6 +779,53 @@ func TestRunWithFakeClient(t *testing.T) {
 	assert.Equal(t, options.name, lr.Name)
 }
 
+func TestRunPrintsCreatedObject(t *testing.T) {
+	newCreatedOptions := func(output string) (*LimitOptions, *fake.Clientset) {
+		fakeClientset := fake.NewSimpleClientset()
+		// Assign what the API server would to the created object
+		fakeClientset.PrependReactor("create", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			created := action.(k8stesting.CreateAction).GetObject().(*v1.LimitRange).DeepCopy()
+			created.UID = "5d8f6c2a-0000-4000-8000-000000000001"
+			created.ResourceVersion = "4242"
+			created.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl-create-limitrange", Operation: metav1.ManagedFieldsOperationUpdate}}
+			return true, created, nil
+		})
+		options := &LimitOptions{
+			name:       "test-limitrange",
+			namespace:  "default",
+			maxCPU:     "1",
+			output:     output,
+			printFlags: newPrintFlags(),
+			IOStreams:  genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+		}
+		options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+		return options, fakeClientset
+	}
+
+	options, fakeClientset := newCreatedOptions("yaml")
+	assert.NoError(t, options.Run())
+	assert.Len(t, fakeClientset.Actions(), 1)
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "kind: LimitRange\n")
+	assert.Contains(t, output, "uid: 5d8f6c2a-0000-4000-8000-000000000001\n")
+	assert.Contains(t, output, "resourceVersion: \"4242\"\n")
+	assert.NotContains(t, output, "managedFields")
+	assert.NotContains(t, output, "created")
+
+	options, _ = newCreatedOptions("json")
+	options.printFlags.JSONYamlPrintFlags.ShowManagedFields = true
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), `"manager": "kubectl-create-limitrange"`)
+
+	options, _ = newCreatedOptions("jsonpath={.metadata.uid} {.metadata.resourceVersion}")
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "5d8f6c2a-0000-4000-8000-000000000001 4242", options.IOStreams.Out.(*bytes.Buffer).String())
+
+	options, _ = newCreatedOptions("")
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "limitrange.core \"test-limitrange\" created\n", options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
 func TestRunDryRunClient(t *testing.T) {
 	options := &LimitOptions{
 		name:      "test-limitrange",
@@ -306,6 +896,126 @@ func TestRunDryRunServer(t *testing.T) {
 	assert.Error(t, err, "expected error getting LimitRange, since it should not be created in dry-run=server mode")
 }
 
+func TestRunDryRunClientWithPVCLimits(t *testing.T) {
+	options := &LimitOptions{
+		name:          "test-limitrange",
+		namespace:     "default",
+		maxPVCStorage: "50Gi",
+		dryRun:        "client",
+		output:        "yaml",
+		IOStreams: genericclioptions.IOStreams{
+			Out: new(bytes.Buffer),
+		},
+	}
+
+	err := options.Run()
+	assert.NoError(t, err, "expected no error during Run with dry-run=client")
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "type: PersistentVolumeClaim")
+	assert.Contains(t, output, "storage: 50Gi")
+}
+
+func TestRunDryRunServerWithPVCLimits(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+
+	options := &LimitOptions{
+		name:          "test-limitrange",
+		namespace:     "default",
+		maxCPU:        "1",
+		minPVCStorage: "1Gi",
+		dryRun:        "server",
+		output:        "json",
+		IOStreams:     genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+
+	var sent *v1.LimitRange
+	fakeClientset.Fake.PrependReactor("create", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		createAction := action.(k8stesting.CreateAction)
+		sent = createAction.GetObject().(*v1.LimitRange)
+		return true, sent, nil
+	})
+
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+
+	err := options.Run()
+	assert.NoError(t, err, "expected no error during Run with dry-run=server")
+
+	if assert.NotNil(t, sent) && assert.Len(t, sent.Spec.Limits, 2) {
+		assert.Equal(t, v1.LimitTypePersistentVolumeClaim, sent.Spec.Limits[1].Type)
+	}
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "\"type\": \"PersistentVolumeClaim\"")
+	assert.Contains(t, output, "\"storage\": \"1Gi\"")
+}
+
+func TestRunDryRunWithoutOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*LimitOptions)
+		expected string
+	}{
+		{
+			name:     "client",
+			modify:   func(o *LimitOptions) { o.dryRun = "client" },
+			expected: "limitrange/test-limitrange created (dry run)\n",
+		},
+		{
+			name:     "client server-side",
+			modify:   func(o *LimitOptions) { o.dryRun, o.serverSide, o.fieldManager = "client", true, "kubectl-lr" },
+			expected: "limitrange/test-limitrange serverside-applied (dry run)\n",
+		},
+		{
+			name:     "server",
+			modify:   func(o *LimitOptions) { o.dryRun = "server" },
+			expected: "limitrange/test-limitrange created (server dry run)\n",
+		},
+		{
+			name:     "server overwrite",
+			modify:   func(o *LimitOptions) { o.dryRun, o.overwrite, o.name = "server", true, "existing" },
+			expected: "limitrange/existing configured (server dry run)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClientset := fake.NewSimpleClientset(&v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}})
+			options := &LimitOptions{
+				name:       "test-limitrange",
+				namespace:  "default",
+				maxCPU:     "1",
+				printFlags: newPrintFlags(),
+				IOStreams:  genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+			}
+			options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+			tt.modify(options)
+
+			assert.NoError(t, options.Run())
+			assert.Equal(t, tt.expected, options.IOStreams.Out.(*bytes.Buffer).String())
+		})
+	}
+}
+
+func TestRunDryRunClientRejectsInconsistentLimits(t *testing.T) {
+	options := &LimitOptions{
+		name:      "test-limitrange",
+		namespace: "default",
+		minCPU:    "2",
+		maxCPU:    "1",
+		dryRun:    "client",
+		output:    "yaml",
+		IOStreams: genericclioptions.IOStreams{
+			Out: new(bytes.Buffer),
+		},
+	}
+
+	err := options.Run()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--min-cpu=2 must not be greater than --max-cpu=1")
+	}
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
 func TestRunWithInvalidDryRunOption(t *testing.T) {
 	options := &LimitOptions{
 		name:      "test-limitrange",
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Version is the version of the plugin recorded by --provenance. Release builds set it with
+//
+//	go build -ldflags "-X k8s.io/sample-cli-plugin/pkg/cmd.Version=v1.2.3" ./cmd/kubectl-create-lr
+var Version = "dev"
+
+// Annotations written by --provenance
+const (
+	provenancePrefix              = "kubectl-lr/"
+	provenanceVersionAnnotation   = provenancePrefix + "version"
+	provenanceUserAnnotation      = provenancePrefix + "user"
+	provenanceTimestampAnnotation = provenancePrefix + "timestamp"
+)
+
+// parseKeyValues parses the KEY=VALUE entries of flagName. The value may be empty, the key may not.
+func parseKeyValues(flagName string, entries []string) (map[string]string, error) {
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, found := strings.Cut(entry, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --%s value %q: must be KEY=VALUE", flagName, entry)
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("invalid --%s value %q: key %q is given more than once", flagName, entry, key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// validateMetadata checks the --label and --annotation values with the rules the API server applies
+func (o *LimitOptions) validateMetadata() error {
+	labels, err := parseKeyValues("label", o.labels)
+	if err != nil {
+		return err
+	}
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid --label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid --label value %q for key %q: %s", value, key, strings.Join(errs, "; "))
+		}
+	}
+
+	annotations, err := parseKeyValues("annotation", o.annotations)
+	if err != nil {
+		return err
+	}
+	for key := range annotations {
+		// Annotation keys are checked case-insensitively, as the API server does
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			return fmt.Errorf("invalid --annotation key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if o.provenance && strings.HasPrefix(key, provenancePrefix) {
+			return fmt.Errorf("invalid --annotation key %q: the %s prefix is reserved for --provenance", key, provenancePrefix)
+		}
+	}
+	if err := apivalidation.ValidateAnnotationsSize(annotations); err != nil {
+		return fmt.Errorf("invalid --annotation values: %w", err)
+	}
+	return nil
+}
+
+// applyMetadata adds the --label and --annotation values, and the provenance annotations
+// computed by Complete, to limitRange. With --save-config, it then records limitRange in the
+// last-applied-configuration annotation, so it must run once the object is complete.
+// Entries that cannot be parsed are left out; Validate reports them.
+func (o *LimitOptions) applyMetadata(limitRange *v1.LimitRange) {
+	labels, _ := parseKeyValues("label", o.labels)
+	annotations, _ := parseKeyValues("annotation", o.annotations)
+	for key, value := range o.provenanceAnnotations {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = value
+	}
+
+	limitRange.Labels = mergeInto(limitRange.Labels, labels)
+	limitRange.Annotations = mergeInto(limitRange.Annotations, annotations)
+
+	if o.saveConfig {
+		if configuration, err := lastAppliedConfiguration(limitRange); err == nil {
+			limitRange.Annotations = mergeInto(limitRange.Annotations, map[string]string{v1.LastAppliedConfigAnnotation: configuration})
+		}
+	}
+}
+
+// mergeInto copies values into target, allocating it when there is something to copy
+func mergeInto(target, values map[string]string) map[string]string {
+	if len(values) > 0 && target == nil {
+		target = make(map[string]string, len(values))
+	}
+	for key, value := range values {
+		target[key] = value
+	}
+	return target
+}
+
+// lastAppliedConfiguration returns the value of the last-applied-configuration annotation for
+// limitRange, encoded as kubectl create --save-config does: compact JSON of the object without
+// the annotation itself. kubectl apply compares against it to find the fields to remove.
+func lastAppliedConfiguration(limitRange *v1.LimitRange) (string, error) {
+	configuration := limitRange.DeepCopy()
+	delete(configuration.Annotations, v1.LastAppliedConfigAnnotation)
+	data, err := runtime.Encode(scheme.Codecs.LegacyCodec(v1.SchemeGroupVersion), configuration)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the last applied configuration: %w", err)
+	}
+	return string(data), nil
+}
+
+// provenanceStamp returns the annotations recording the plugin version, the kubeconfig user and
+// now. The user is left out when the kubeconfig does not name one.
+func (o *LimitOptions) provenanceStamp(now time.Time) (map[string]string, error) {
+	annotations := map[string]string{
+		provenanceVersionAnnotation:   Version,
+		provenanceTimestampAnnotation: now.UTC().Format(time.RFC3339),
+	}
+	user, err := o.kubeconfigUser()
+	if err != nil {
+		return nil, err
+	}
+	if user != "" {
+		annotations[provenanceUserAnnotation] = user
+	}
+	return annotations, nil
+}
+
+// withLiveTimestamp returns limitRange with the --provenance timestamp of live instead of the
+// current time, refreshing the saved configuration that records it too. Comparing that with live
+// tells whether anything but the time would change. limitRange is returned as is when either
+// of them carries no timestamp.
+func withLiveTimestamp(limitRange, live *v1.LimitRange) *v1.LimitRange {
+	timestamp, ok := live.Annotations[provenanceTimestampAnnotation]
+	if _, stamped := limitRange.Annotations[provenanceTimestampAnnotation]; !ok || !stamped {
+		return limitRange
+	}
+	restamped := limitRange.DeepCopy()
+	restamped.Annotations[provenanceTimestampAnnotation] = timestamp
+	if _, saved := restamped.Annotations[v1.LastAppliedConfigAnnotation]; saved {
+		if configuration, err := lastAppliedConfiguration(restamped); err == nil {
+			restamped.Annotations[v1.LastAppliedConfigAnnotation] = configuration
+		}
+	}
+	return restamped
+}
+
+// kubeconfigUser returns the name of the kubeconfig user the command runs as, taking
+// --user and --context into account
+func (o *LimitOptions) kubeconfigUser() (string, error) {
+	if o.configFlags.AuthInfoName != nil && *o.configFlags.AuthInfoName != "" {
+		return *o.configFlags.AuthInfoName, nil
+	}
+	rawConfig, err := o.configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+	contextName := rawConfig.CurrentContext
+	if o.configFlags.Context != nil && *o.configFlags.Context != "" {
+		contextName = *o.configFlags.Context
+	}
+	if context, ok := rawConfig.Contexts[contextName]; ok {
+		return context.AuthInfo, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const provenanceKubeconfig = `
+apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context: {cluster: dev, user: alice}
+- name: prod
+  context: {cluster: prod, user: deployer}
+clusters:
+- name: dev
+  cluster: {server: "https://dev.example.com"}
+- name: prod
+  cluster: {server: "https://prod.example.com"}
+users:
+- name: alice
+  user: {token: secret}
+- name: deployer
+  user: {token: secret}
+`
+
+func TestParseKeyValues(t *testing.T) {
+	values, err := parseKeyValues("label", []string{"team=payments", "empty=", "url=a=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "empty": "", "url": "a=b"}, values)
+
+	_, err = parseKeyValues("label", []string{"team"})
+	assert.EqualError(t, err, `invalid --label value "team": must be KEY=VALUE`)
+
+	_, err = parseKeyValues("annotation", []string{"=value"})
+	assert.EqualError(t, err, `invalid --annotation value "=value": must be KEY=VALUE`)
+
+	_, err = parseKeyValues("label", []string{"team=a", "team=b"})
+	assert.EqualError(t, err, `invalid --label value "team=b": key "team" is given more than once`)
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     LimitOptions
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			options: LimitOptions{
+				labels:      []string{"team=payments", "example.com/cost-center=cc-42", "empty="},
+				annotations: []string{"owner=Payments Team <payments@example.com>", "Example.com/Note=free text, with commas"},
+			},
+		},
+		{
+			name:        "label key",
+			options:     LimitOptions{labels: []string{"-team=payments"}},
+			expectedErr: `invalid --label key "-team": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`,
+		},
+		{
+			name:        "label value",
+			options:     LimitOptions{labels: []string{"owner=payments@example.com"}},
+			expectedErr: `invalid --label value "payments@example.com" for key "owner": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')`,
+		},
+		{
+			name:        "annotation key",
+			options:     LimitOptions{annotations: []string{"a/b/c=value"}},
+			expectedErr: `invalid --annotation key "a/b/c": a qualified name must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]') with an optional DNS subdomain prefix and '/' (e.g. 'example.com/MyName')`,
+		},
+		{
+			name:        "annotation size",
+			options:     LimitOptions{annotations: []string{"large=" + strings.Repeat("x", 256*1024)}},
+			expectedErr: "invalid --annotation values: annotations size 262149 is larger than limit 262144",
+		},
+		{
+			name:        "reserved prefix",
+			options:     LimitOptions{annotations: []string{"kubectl-lr/user=bob"}, provenance: true},
+			expectedErr: `invalid --annotation key "kubectl-lr/user": the kubectl-lr/ prefix is reserved for --provenance`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.validateMetadata()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestProvenanceStamp(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(provenanceKubeconfig), 0o600))
+	now := time.Date(2025, 3, 14, 15, 9, 26, 0, time.FixedZone("CET", 3600))
+
+	newOptions := func() *LimitOptions {
+		options := &LimitOptions{configFlags: genericclioptions.NewConfigFlags(true)}
+		options.configFlags.KubeConfig = &kubeconfig
+		return options
+	}
+
+	options := newOptions()
+	annotations, err := options.provenanceStamp(now)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"kubectl-lr/version":   "dev",
+		"kubectl-lr/user":      "alice",
+		"kubectl-lr/timestamp": "2025-03-14T14:09:26Z",
+	}, annotations)
+
+	options = newOptions()
+	contextName := "prod"
+	options.configFlags.Context = &contextName
+	annotations, err = options.provenanceStamp(now)
+	assert.NoError(t, err)
+	assert.Equal(t, "deployer", annotations["kubectl-lr/user"], "--context selects the user")
+
+	options = newOptions()
+	user := "bob"
+	options.configFlags.AuthInfoName = &user
+	annotations, err = options.provenanceStamp(now)
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", annotations["kubectl-lr/user"], "--user wins")
+}
+
+func TestRunDryRunClientWithMetadata(t *testing.T) {
+	options := &LimitOptions{
+		name:                  "test-limitrange",
+		namespace:             "default",
+		maxCPU:                "1",
+		dryRun:                "client",
+		output:                "yaml",
+		labels:                []string{"team=payments", "cost-center=cc-42"},
+		annotations:           []string{"owner=payments@example.com"},
+		provenance:            true,
+		provenanceAnnotations: map[string]string{"kubectl-lr/version": "v1.2.3"},
+		IOStreams:             genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), `metadata:
+  annotations:
+    kubectl-lr/version: v1.2.3
+    owner: payments@example.com
+  creationTimestamp: null
+  labels:
+    cost-center: cc-42
+    team: payments
+  name: test-limitrange
+`)
+}
+
+func TestApplyMetadataKeepsSpecLabels(t *testing.T) {
+	options := &LimitOptions{labels: []string{"team=payments"}, annotations: []string{"note=x"}}
+	limitRange := &v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"env": "prod", "team": "other"}}}
+
+	options.applyMetadata(limitRange)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "payments"}, limitRange.Labels)
+	assert.Equal(t, map[string]string{"note": "x"}, limitRange.Annotations)
+
+	empty := &v1.LimitRange{}
+	(&LimitOptions{}).applyMetadata(empty)
+	assert.Nil(t, empty.Labels)
+	assert.Nil(t, empty.Annotations)
+}
+
+func TestRunOverwriteLabels(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	options := newOverwriteOptions(fakeClientset)
+	// Same limits as the existing object, so only the label changes
+	options.maxCPU = "1"
+	options.labels = []string{"team=payments"}
+
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "configured")
+	updated, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments"}, updated.Labels)
+
+	options = newOverwriteOptions(fakeClientset)
+	options.maxCPU = "1"
+	options.labels = []string{"team=payments"}
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "unchanged")
+}
+
+func TestLastAppliedConfiguration(t *testing.T) {
+	limitRange := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-limitrange",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"owner":                        "payments@example.com",
+				v1.LastAppliedConfigAnnotation: "stale",
+			},
+		},
+		Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{
+			Type: v1.LimitTypeContainer,
+			Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		}}},
+	}
+
+	configuration, err := lastAppliedConfiguration(limitRange)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kind":"LimitRange","apiVersion":"v1","metadata":{"name":"test-limitrange","namespace":"default","creationTimestamp":null,`+
+		`"annotations":{"owner":"payments@example.com"}},"spec":{"limits":[{"type":"Container","max":{"cpu":"1"}}]}}`+"\n", configuration)
+	// The object itself is left untouched
+	assert.Equal(t, "stale", limitRange.Annotations[v1.LastAppliedConfigAnnotation])
+}
+
+func TestRunDryRunClientSaveConfig(t *testing.T) {
+	options := &LimitOptions{
+		name:       "test-limitrange",
+		namespace:  "default",
+		maxCPU:     "1",
+		dryRun:     "client",
+		output:     "yaml",
+		labels:     []string{"team=payments"},
+		saveConfig: true,
+		IOStreams:  genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), `metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"kind":"LimitRange","apiVersion":"v1","metadata":{"name":"test-limitrange","namespace":"default","creationTimestamp":null,"labels":{"team":"payments"}},"spec":{"limits":[{"type":"Container","max":{"cpu":"1"}}]}}
+  creationTimestamp: null
+  labels:
+    team: payments
+`)
+}
+
+func TestValidateSaveConfigServerSide(t *testing.T) {
+	options := &LimitOptions{name: "test-limitrange", namespace: "default", maxCPU: "1", saveConfig: true, serverSide: true, fieldManager: "kubectl-create-lr"}
+	assert.EqualError(t, options.Validate(), "--save-config cannot be combined with --server-side")
+}
+
+func TestRunOverwriteSaveConfig(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	options := newOverwriteOptions(fakeClientset)
+	options.saveConfig = true
+
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "configured")
+	updated, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kind":"LimitRange","apiVersion":"v1","metadata":{"name":"test-limitrange","namespace":"default","creationTimestamp":null},`+
+		`"spec":{"limits":[{"type":"Container","max":{"cpu":"2"}}]}}`+"\n", updated.Annotations[v1.LastAppliedConfigAnnotation])
+
+	// Saving the same configuration again is not a change
+	options = newOverwriteOptions(fakeClientset)
+	options.saveConfig = true
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "unchanged")
+}
+
+func TestRunOverwriteProvenance(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	newOptions := func(timestamp string) *LimitOptions {
+		options := newOverwriteOptions(fakeClientset)
+		options.provenance = true
+		options.saveConfig = true
+		options.provenanceAnnotations = map[string]string{
+			provenanceVersionAnnotation:   "dev",
+			provenanceTimestampAnnotation: timestamp,
+		}
+		return options
+	}
+
+	options := newOptions("2025-03-14T14:09:26Z")
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "configured")
+
+	// A later run with the same values only has a newer timestamp
+	options = newOptions("2025-06-01T08:00:00Z")
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "unchanged")
+	updated, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-03-14T14:09:26Z", updated.Annotations[provenanceTimestampAnnotation])
+
+	options = newOptions("2025-06-01T08:00:00Z")
+	options.maxCPU = "3"
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "configured")
+	updated, err = fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-06-01T08:00:00Z", updated.Annotations[provenanceTimestampAnnotation])
+	assert.Contains(t, updated.Annotations[v1.LastAppliedConfigAnnotation], `"kubectl-lr/timestamp":"2025-06-01T08:00:00Z"`)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// overwriteLimitRange creates limitRange, or updates the existing LimitRange of the same name
+// by replacing its items of the types limitRange defines. Updates are guarded by the
+// resourceVersion that was read and retried when another writer got there first.
+func (o *LimitOptions) overwriteLimitRange(clientset kubernetes.Interface, limitRange *v1.LimitRange) error {
+	client := clientset.CoreV1().LimitRanges(limitRange.Namespace)
+
+	var dryRun []string
+	if o.dryRun == "server" {
+		dryRun = []string{"All"}
+	}
+
+	var result *v1.LimitRange
+	unchanged := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing, err := client.Get(context.TODO(), limitRange.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			// Nothing to overwrite, fall back to a plain create
+			result = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if sameLimitRange(existing, overwrittenLimitRange(existing, withLiveTimestamp(limitRange, existing))) {
+			result, unchanged = existing, true
+			return nil
+		}
+
+		updated := overwrittenLimitRange(existing, limitRange)
+		result, err = client.Update(context.TODO(), updated, metav1.UpdateOptions{DryRun: dryRun})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update LimitRange: %w", err)
+	}
+	if result == nil && !unchanged {
+		return o.createLimitRange(clientset, limitRange)
+	}
+
+	// Print the server response for server-side dry-run, or when an output format is requested
+	if o.dryRun == "server" || o.output != "" {
+		operation := "configured"
+		if unchanged {
+			operation = "unchanged"
+		}
+		return o.printOutputWithTypeMeta(result, operation)
+	}
+
+	if unchanged {
+		fmt.Fprintf(o.IOStreams.Out, "limitrange.core %q unchanged\n", limitRange.Name)
+	} else {
+		fmt.Fprintf(o.IOStreams.Out, "limitrange.core %q configured\n", limitRange.Name)
+	}
+	return nil
+}
+
+// overwrittenLimitRange returns existing with the items of limitRange merged in, see
+// mergeLimitRangeItems, along with its labels and annotations
+func overwrittenLimitRange(existing, limitRange *v1.LimitRange) *v1.LimitRange {
+	updated := existing.DeepCopy()
+	updated.Spec.Limits = mergeLimitRangeItems(existing.Spec.Limits, limitRange.Spec.Limits)
+	// Carry over the labels and annotations of limitRange, including its saved configuration
+	updated.Labels = mergeInto(updated.Labels, limitRange.Labels)
+	updated.Annotations = mergeInto(updated.Annotations, limitRange.Annotations)
+	// The stored object carries the defaults the server derived, so derive them
+	// here too or a re-run would always look like a change
+	for i := range updated.Spec.Limits {
+		applyServerDefaults(&updated.Spec.Limits[i])
+	}
+	return updated
+}
+
+// sameLimitRange reports whether a and b have the same spec, labels and annotations
+func sameLimitRange(a, b *v1.LimitRange) bool {
+	return apiequality.Semantic.DeepEqual(a.Spec, b.Spec) &&
+		maps.Equal(a.Labels, b.Labels) && maps.Equal(a.Annotations, b.Annotations)
+}
+
+// mergeLimitRangeItems returns existing with the items whose type appears in desired replaced,
+// in place, by the desired items of that type. Items of other types are kept as they are and
+// desired types missing from existing are appended.
+func mergeLimitRangeItems(existing, desired []v1.LimitRangeItem) []v1.LimitRangeItem {
+	byType := map[v1.LimitType][]v1.LimitRangeItem{}
+	var desiredTypes []v1.LimitType
+	for _, item := range desired {
+		if _, ok := byType[item.Type]; !ok {
+			desiredTypes = append(desiredTypes, item.Type)
+		}
+		byType[item.Type] = append(byType[item.Type], *item.DeepCopy())
+	}
+
+	merged := make([]v1.LimitRangeItem, 0, len(existing)+len(desired))
+	placed := map[v1.LimitType]bool{}
+	for _, item := range existing {
+		replacements, replace := byType[item.Type]
+		if !replace {
+			merged = append(merged, *item.DeepCopy())
+			continue
+		}
+		if !placed[item.Type] {
+			merged = append(merged, replacements...)
+			placed[item.Type] = true
+		}
+	}
+	for _, limitType := range desiredTypes {
+		if !placed[limitType] {
+			merged = append(merged, byType[limitType]...)
+		}
+	}
+	return merged
+}
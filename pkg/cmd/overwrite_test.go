@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newOverwriteOptions(fakeClientset kubernetes.Interface) *LimitOptions {
+	options := &LimitOptions{
+		name:      "test-limitrange",
+		namespace: "default",
+		maxCPU:    "2",
+		overwrite: true,
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return options
+}
+
+func existingLimitRange() *v1.LimitRange {
+	return &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-limitrange", Namespace: "default", ResourceVersion: "1"},
+		Spec: v1.LimitRangeSpec{
+			Limits: []v1.LimitRangeItem{
+				{
+					Type:           v1.LimitTypeContainer,
+					Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				},
+				{
+					Type: v1.LimitTypePersistentVolumeClaim,
+					Max:  v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			},
+		},
+	}
+}
+
+func TestRunOverwriteCreatesMissingLimitRange(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	options := newOverwriteOptions(fakeClientset)
+
+	assert.NoError(t, options.Run())
+
+	_, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "limitrange.core \"test-limitrange\" created")
+}
+
+func TestRunOverwriteConfigured(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	options := newOverwriteOptions(fakeClientset)
+
+	assert.NoError(t, options.Run())
+
+	lr, err := fakeClientset.CoreV1().LimitRanges("default").Get(context.TODO(), "test-limitrange", metav1.GetOptions{})
+	if assert.NoError(t, err) && assert.Len(t, lr.Spec.Limits, 2) {
+		maxCPU := lr.Spec.Limits[0].Max[v1.ResourceCPU]
+		defaultCPU := lr.Spec.Limits[0].Default[v1.ResourceCPU]
+		assert.Equal(t, "2", (&maxCPU).String())
+		assert.Equal(t, "2", (&defaultCPU).String(), "derived defaults follow the new max")
+		assert.Equal(t, v1.LimitTypePersistentVolumeClaim, lr.Spec.Limits[1].Type, "items of other types are kept")
+	}
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "limitrange.core \"test-limitrange\" configured")
+}
+
+func TestRunOverwriteUnchanged(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	options := newOverwriteOptions(fakeClientset)
+	options.maxCPU = "1000m"
+
+	updated := false
+	fakeClientset.Fake.PrependReactor("update", "limitranges", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		updated = true
+		return false, nil, nil
+	})
+
+	assert.NoError(t, options.Run())
+	assert.False(t, updated, "expected no update for an unchanged LimitRange")
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "limitrange.core \"test-limitrange\" unchanged")
+}
+
+func TestRunOverwriteOutput(t *testing.T) {
+	options := newOverwriteOptions(fake.NewSimpleClientset(existingLimitRange()))
+	options.output = "name"
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, "limitrange/test-limitrange\n", options.IOStreams.Out.(*bytes.Buffer).String())
+
+	options = newOverwriteOptions(fake.NewSimpleClientset(existingLimitRange()))
+	options.output = "yaml"
+	assert.NoError(t, options.Run())
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "cpu: \"2\"")
+	assert.NotContains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "configured")
+}
+
+func TestRunOverwriteRetriesOnConflict(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	options := newOverwriteOptions(fakeClientset)
+
+	attempts := 0
+	fakeClientset.Fake.PrependReactor("update", "limitranges", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		if attempts == 1 {
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "limitranges"}, "test-limitrange", nil)
+		}
+		return false, nil, nil
+	})
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, 2, attempts, "expected the update to be retried once")
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "configured")
+}
+
+func TestRunOverwriteDryRunServer(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(existingLimitRange())
+	options := newOverwriteOptions(fakeClientset)
+	options.dryRun = "server"
+	options.output = "yaml"
+
+	var updateOptions metav1.UpdateOptions
+	fakeClientset.Fake.PrependReactor("update", "limitranges", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updateAction := action.(k8stesting.UpdateActionImpl)
+		updateOptions = updateAction.UpdateOptions
+		return true, updateAction.GetObject(), nil
+	})
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, []string{"All"}, updateOptions.DryRun)
+	assert.Contains(t, options.IOStreams.Out.(*bytes.Buffer).String(), "cpu: \"2\"")
+}
+
+func TestValidateOverwriteWithServerSide(t *testing.T) {
+	options := &LimitOptions{
+		name:         "test-limitrange",
+		namespace:    "default",
+		maxCPU:       "1",
+		overwrite:    true,
+		serverSide:   true,
+		fieldManager: defaultFieldManager,
+	}
+
+	err := options.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--overwrite cannot be combined with --server-side")
+	}
+}
+
+func TestMergeLimitRangeItems(t *testing.T) {
+	existing := []v1.LimitRangeItem{
+		{Type: v1.LimitTypeContainer, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+		{Type: v1.LimitTypePod, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}},
+		{Type: v1.LimitTypeContainer, Max: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")}},
+	}
+	desired := []v1.LimitRangeItem{
+		{Type: v1.LimitTypePersistentVolumeClaim, Max: v1.ResourceList{v1.ResourceStorage: resource.MustParse("5Gi")}},
+		{Type: v1.LimitTypeContainer, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")}},
+	}
+
+	merged := mergeLimitRangeItems(existing, desired)
+
+	if assert.Len(t, merged, 3) {
+		assert.Equal(t, v1.LimitTypeContainer, merged[0].Type)
+		maxCPU := merged[0].Max[v1.ResourceCPU]
+		assert.Equal(t, "2", (&maxCPU).String())
+		assert.Equal(t, v1.LimitTypePod, merged[1].Type)
+		assert.Equal(t, v1.LimitTypePersistentVolumeClaim, merged[2].Type)
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	presetsExample = `
+    # List the built-in and user-defined presets with their values
+    kubectl lr presets
+
+    # Print the presets in the format of the presets file
+    kubectl lr presets -o yaml
+    `
+)
+
+const (
+	presetSourceBuiltin = "built-in"
+	presetSourceUser    = "user"
+)
+
+// limitRangePreset is a named set of LimitRange items that --preset starts from
+type limitRangePreset struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Limits      []v1.LimitRangeItem `json:"limits"`
+
+	// source tells whether the preset is built in or comes from the presets file
+	source string
+}
+
+// presetsFileContent is the layout of the user presets file
+type presetsFileContent struct {
+	Presets []limitRangePreset `json:"presets"`
+}
+
+// builtinPresets are the LimitRange shapes shipped with the plugin
+var builtinPresets = []limitRangePreset{
+	{
+		Name:        "small",
+		Description: "Small services and interactive workloads",
+		Limits: []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Min:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m"), v1.ResourceMemory: resource.MustParse("64Mi")},
+				Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("256Mi")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+			},
+		},
+	},
+	{
+		Name:        "batch",
+		Description: "Batch jobs with large containers and a cap per pod",
+		Limits: []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")},
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("2Gi")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			{
+				Type: v1.LimitTypePod,
+				Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("16"), v1.ResourceMemory: resource.MustParse("32Gi")},
+			},
+		},
+	},
+	{
+		Name:        "gpu-dev",
+		Description: "GPU development namespaces, large CPU and memory allowances for notebooks and training jobs",
+		Limits: []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("8"), v1.ResourceMemory: resource.MustParse("32Gi")},
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("8Gi")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			{
+				// GPUs are left uncapped: LimitRanger rejects every pod that sets no limit for a
+				// resource with a max, which would turn away all CPU-only pods in the namespace
+				Type: v1.LimitTypePod,
+				Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("16"), v1.ResourceMemory: resource.MustParse("64Gi")},
+			},
+		},
+	},
+}
+
+// PresetsOptions holds information required to list the available presets
+type PresetsOptions struct {
+	presetsFile string
+	output      string
+	IOStreams   genericclioptions.IOStreams
+}
+
+// NewCmdPresets creates a cobra command listing the presets accepted by --preset
+func NewCmdPresets(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &PresetsOptions{
+		presetsFile: defaultPresetsFile(),
+		IOStreams:   streams,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "presets [flags]",
+		Short:        "List the LimitRange presets available to --preset",
+		Long:         fmt.Sprintf("List the built-in presets and the user-defined presets read from %s.", o.presetsFile),
+		Example:      presetsExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	cmd.Flags().StringVar(&o.presetsFile, "presets-file", o.presetsFile, "File holding user-defined presets")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "Output format. One of: yaml|json. Defaults to a table")
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Run prints the available presets as a table, or in the presets file format
+func (o *PresetsOptions) Run() error {
+	presets, err := loadPresets(o.presetsFile)
+	if err != nil {
+		return err
+	}
+
+	switch o.output {
+	case "":
+		return printPresetsTable(o.IOStreams, presets)
+	case "yaml", "json":
+		var output []byte
+		content := presetsFileContent{Presets: presets}
+		if o.output == "yaml" {
+			output, err = yaml.Marshal(content)
+		} else {
+			output, err = json.MarshalIndent(content, "", "    ")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Fprintf(o.IOStreams.Out, "%s\n", output)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.output)
+	}
+}
+
+// printPresetsTable prints one row per preset resource, naming each preset on its first row
+func printPresetsTable(streams genericclioptions.IOStreams, presets []limitRangePreset) error {
+	w := printers.GetNewTabWriter(streams.Out)
+	fmt.Fprintf(w, "NAME\tSOURCE\t%s\tDESCRIPTION\n", strings.Join(limitTableHeader, "\t"))
+	for _, preset := range presets {
+		first := true
+		for _, item := range preset.Limits {
+			for _, row := range limitItemRows(item) {
+				name, source, description := "", "", ""
+				if first {
+					name, source, description = preset.Name, preset.source, preset.Description
+					first = false
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, source, strings.Join(row, "\t"), description)
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// defaultPresetsFile returns the location of the user presets file in the user's config dir
+func defaultPresetsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubectl-lr", "presets.yaml")
+}
+
+// loadPresets returns the built-in presets followed by the ones read from path.
+// User-defined presets replace built-in presets of the same name; a missing file is not an error.
+func loadPresets(path string) ([]limitRangePreset, error) {
+	presets := make([]limitRangePreset, 0, len(builtinPresets))
+	for _, preset := range builtinPresets {
+		preset.source = presetSourceBuiltin
+		presets = append(presets, preset)
+	}
+	if path == "" {
+		return presets, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return presets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets file: %w", err)
+	}
+
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets file %s: %w", path, err)
+	}
+	var content presetsFileContent
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, fmt.Errorf("failed to read presets file %s: %w", path, err)
+	}
+
+	for _, preset := range content.Presets {
+		if preset.Name == "" {
+			return nil, fmt.Errorf("failed to read presets file %s: every preset needs a name", path)
+		}
+		preset.source = presetSourceUser
+		normalizeLimitTypes(preset.Limits)
+
+		replaced := false
+		for i := range presets {
+			if presets[i].Name == preset.Name {
+				presets[i] = preset
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			presets = append(presets, preset)
+		}
+	}
+	return presets, nil
+}
+
+// findPreset returns the preset called name
+func findPreset(presets []limitRangePreset, name string) (*limitRangePreset, error) {
+	names := make([]string, 0, len(presets))
+	for i := range presets {
+		if presets[i].Name == name {
+			return &presets[i], nil
+		}
+		names = append(names, presets[i].Name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown preset %q, must be one of: %s", name, strings.Join(names, ", "))
+}
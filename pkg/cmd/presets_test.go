@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const testPresetsFile = `
+presets:
+- name: tiny
+  description: Tiny sidecars
+  limits:
+  - type: container
+    max:
+      cpu: 100m
+      memory: 64Mi
+- name: small
+  description: Overridden small preset
+  limits:
+  - max:
+      cpu: 500m
+`
+
+func writePresetsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "presets.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPresetsBuiltinOnly(t *testing.T) {
+	presets, err := loadPresets(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NoError(t, err)
+
+	if assert.Len(t, presets, len(builtinPresets)) {
+		for _, preset := range presets {
+			assert.Equal(t, presetSourceBuiltin, preset.source)
+		}
+	}
+}
+
+func TestLoadPresetsWithUserFile(t *testing.T) {
+	presets, err := loadPresets(writePresetsFile(t, testPresetsFile))
+	assert.NoError(t, err)
+
+	small, err := findPreset(presets, "small")
+	if assert.NoError(t, err) {
+		assert.Equal(t, presetSourceUser, small.source)
+		assert.Equal(t, "Overridden small preset", small.Description)
+		assert.Equal(t, v1.LimitTypeContainer, small.Limits[0].Type)
+	}
+
+	tiny, err := findPreset(presets, "tiny")
+	if assert.NoError(t, err) {
+		assert.Equal(t, presetSourceUser, tiny.source)
+		assert.Equal(t, v1.LimitTypeContainer, tiny.Limits[0].Type)
+	}
+
+	assert.Len(t, presets, len(builtinPresets)+1)
+}
+
+func TestLoadPresetsInvalidFile(t *testing.T) {
+	_, err := loadPresets(writePresetsFile(t, "presets:\n- description: no name\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "every preset needs a name")
+	}
+
+	_, err = loadPresets(writePresetsFile(t, "presets:\n- name: typo\n  limit: []\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown field \"limit\"")
+	}
+}
+
+func TestFindPresetUnknown(t *testing.T) {
+	_, err := findPreset(builtinPresets, "huge")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown preset \"huge\", must be one of: batch, gpu-dev, small")
+	}
+}
+
+func TestBuiltinPresetsAreValid(t *testing.T) {
+	for i := range builtinPresets {
+		preset := &builtinPresets[i]
+		t.Run(preset.Name, func(t *testing.T) {
+			options := &LimitOptions{
+				name:      "test-limitrange",
+				namespace: "default",
+				preset:    preset,
+			}
+			assert.NoError(t, options.Validate())
+		})
+	}
+}
+
+func TestBuiltinPresetsAdmitPodsWithoutResources(t *testing.T) {
+	for i := range builtinPresets {
+		preset := &builtinPresets[i]
+		t.Run(preset.Name, func(t *testing.T) {
+			options := &LimitOptions{
+				name:      "test-limitrange",
+				namespace: "default",
+				preset:    preset,
+			}
+			limitRanges := options.storedLimitRanges()
+			spec := &v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "nginx"}}}
+
+			applyContainerDefaults(spec, limitRanges)
+			assert.Empty(t, validatePodResources(spec, limitRanges))
+		})
+	}
+}
+
+func TestCreateLimitRangeObjectWithPreset(t *testing.T) {
+	preset, err := findPreset(builtinPresets, "batch")
+	assert.NoError(t, err)
+
+	options := &LimitOptions{
+		name:      "test-limitrange",
+		namespace: "default",
+		preset:    preset,
+		maxMemory: "16Gi",
+		minCPU:    "100m",
+	}
+
+	limitRange := options.createLimitRangeObject()
+
+	if assert.Len(t, limitRange.Spec.Limits, 2) {
+		maxMemory := limitRange.Spec.Limits[0].Max[v1.ResourceMemory]
+		maxCPU := limitRange.Spec.Limits[0].Max[v1.ResourceCPU]
+		minCPU := limitRange.Spec.Limits[0].Min[v1.ResourceCPU]
+		assert.Equal(t, "16Gi", (&maxMemory).String(), "flags override preset values")
+		assert.Equal(t, "4", (&maxCPU).String())
+		assert.Equal(t, "100m", (&minCPU).String())
+	}
+
+	// The preset itself must be left untouched
+	presetMaxMemory := preset.Limits[0].Max[v1.ResourceMemory]
+	assert.Equal(t, "8Gi", (&presetMaxMemory).String())
+	assert.Empty(t, preset.Limits[0].Min)
+}
+
+func TestCompleteWithPreset(t *testing.T) {
+	options := &LimitOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		namespace:   "default",
+		presetName:  "tiny",
+		presetsFile: writePresetsFile(t, testPresetsFile),
+	}
+
+	err := options.Complete(&cobra.Command{}, nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, options.preset) {
+		assert.Equal(t, "tiny", options.preset.Name)
+	}
+
+	options.presetName = "huge"
+	err = options.Complete(&cobra.Command{}, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown preset \"huge\"")
+	}
+}
+
+func TestValidatePresetWithSpecFile(t *testing.T) {
+	options := &LimitOptions{
+		namespace: "default",
+		preset:    &builtinPresets[0],
+		specs:     []limitRangeSpec{{Name: "from-file"}},
+	}
+
+	err := options.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--preset cannot be combined with --filename")
+	}
+}
+
+func TestRunPresetsTable(t *testing.T) {
+	options := &PresetsOptions{
+		presetsFile: writePresetsFile(t, testPresetsFile),
+		IOStreams:   genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+
+	err := options.Run()
+	assert.NoError(t, err)
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "NAME")
+	assert.Contains(t, output, "DEFAULT-REQUEST")
+	assert.Regexp(t, `tiny\s+user\s+Container\s+cpu\s+-\s+100m`, output)
+	assert.Regexp(t, `batch\s+built-in\s+Container\s+cpu`, output)
+	assert.Regexp(t, `Pod\s+cpu\s+-\s+16`, output)
+}
+
+func TestRunPresetsYAML(t *testing.T) {
+	options := &PresetsOptions{
+		output:    "yaml",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+
+	err := options.Run()
+	assert.NoError(t, err)
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "presets:")
+	assert.Contains(t, output, "name: gpu-dev")
+	assert.NotContains(t, output, "source")
+}
+
+func TestRunPresetsUnsupportedOutput(t *testing.T) {
+	options := &PresetsOptions{
+		output:    "xml",
+		IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)},
+	}
+
+	err := options.Run()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unsupported output format: xml")
+	}
+}
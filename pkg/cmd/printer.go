@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// customColumnsPrefix starts the --output value describing custom columns, as in kubectl get
+const customColumnsPrefix = "custom-columns="
+
+// newPrintFlags returns the kubectl print flags used to print objects with --output
+func newPrintFlags() *genericclioptions.PrintFlags {
+	return genericclioptions.NewPrintFlags("").WithTypeSetter(scheme.Scheme)
+}
+
+// addOutputFlags registers --output bound to output, along with the template and
+// --show-managed-fields flags of printFlags. extraFormats lists the formats a command
+// handles on top of printFlags.
+func addOutputFlags(cmd *cobra.Command, printFlags *genericclioptions.PrintFlags, output *string, extraFormats ...string) {
+	printFlags.JSONYamlPrintFlags.AddFlags(cmd)
+	printFlags.TemplatePrinterFlags.AddFlags(cmd)
+	formats := append(printFlags.AllowedFormats(), extraFormats...)
+	cmd.Flags().StringVarP(output, "output", "o", *output, fmt.Sprintf("Output format. One of: (%s).", strings.Join(formats, ", ")))
+	printFlags.OutputFlagSpecified = func() bool {
+		return cmd.Flag("output").Changed
+	}
+}
+
+// withOperation returns a copy of printFlags whose name printer follows the name of each object
+// with operation, such as created or configured, when no --output format is given
+func withOperation(printFlags *genericclioptions.PrintFlags, operation string) *genericclioptions.PrintFlags {
+	if printFlags == nil {
+		printFlags = newPrintFlags()
+	}
+	flags := *printFlags
+	flags.NamePrintFlags = genericclioptions.NewNamePrintFlags(operation)
+	return &flags
+}
+
+// toPrinter returns the printer for output, such as yaml, json, name, jsonpath=... or
+// go-template=..., taking the template flags from printFlags when they are set
+func toPrinter(printFlags *genericclioptions.PrintFlags, output string) (printers.ResourcePrinter, error) {
+	if printFlags == nil {
+		printFlags = newPrintFlags()
+	}
+	flags := *printFlags
+	flags.OutputFormat = &output
+	return flags.ToPrinter()
+}
+
+// printObject prints obj in the output format, see toPrinter
+func printObject(out io.Writer, printFlags *genericclioptions.PrintFlags, output string, obj runtime.Object) error {
+	printer, err := toPrinter(printFlags, output)
+	if err != nil {
+		return err
+	}
+	return printer.PrintObj(obj, out)
+}
+
+// customColumn is one HEADER:JSONPATH column of --output=custom-columns=...
+type customColumn struct {
+	header string
+	parser *jsonpath.JSONPath
+}
+
+// customColumnsPrinter prints objects as a table whose columns are JSONPath expressions
+type customColumnsPrinter struct {
+	columns []customColumn
+}
+
+// newCustomColumnsPrinter parses a comma-separated list of HEADER:JSONPATH columns. The
+// JSONPath may leave out the braces and the leading dot, like .metadata.name or metadata.name.
+func newCustomColumnsPrinter(spec string) (*customColumnsPrinter, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+	printer := &customColumnsPrinter{}
+	for _, column := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(column, ":")
+		if !ok || header == "" || path == "" {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", column)
+		}
+		path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+		if !strings.HasPrefix(path, ".") {
+			path = "." + path
+		}
+		parser := jsonpath.New(header).AllowMissingKeys(true)
+		if err := parser.Parse("{" + path + "}"); err != nil {
+			return nil, fmt.Errorf("failed to parse custom column %s: %w", header, err)
+		}
+		printer.columns = append(printer.columns, customColumn{header: header, parser: parser})
+	}
+	return printer, nil
+}
+
+// PrintObj prints the header and one row per object, or per item when obj is a list.
+// Values a path does not find are shown as <none>.
+func (p *customColumnsPrinter) PrintObj(obj runtime.Object, out io.Writer) error {
+	objects := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		var err error
+		if objects, err = meta.ExtractList(obj); err != nil {
+			return err
+		}
+	}
+
+	w := printers.GetNewTabWriter(out)
+	headers := make([]string, len(p.columns))
+	for i, column := range p.columns {
+		headers[i] = column.header
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, object := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+		if err != nil {
+			return err
+		}
+		cells := make([]string, len(p.columns))
+		for i, column := range p.columns {
+			results, err := column.parser.FindResults(content)
+			if err != nil {
+				return err
+			}
+			var values []string
+			for _, result := range results {
+				for _, value := range result {
+					values = append(values, fmt.Sprint(value.Interface()))
+				}
+			}
+			cells[i] = "<none>"
+			if len(values) > 0 {
+				cells[i] = strings.Join(values, ",")
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
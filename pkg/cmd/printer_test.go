@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestToPrinter(t *testing.T) {
+	for _, output := range []string{"yaml", "json", "name", "", "jsonpath={.metadata.name}", "go-template={{.kind}}"} {
+		_, err := toPrinter(nil, output)
+		assert.NoError(t, err, output)
+	}
+
+	_, err := toPrinter(nil, "jsonpath={.metadata.name")
+	assert.Error(t, err)
+
+	printFlags := newPrintFlags()
+	*printFlags.TemplatePrinterFlags.TemplateArgument = "{{.metadata.name}}"
+	printFlags.OutputFlagSpecified = func() bool { return false }
+	var out bytes.Buffer
+	assert.NoError(t, printObject(&out, printFlags, "", &v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "a"}}))
+	assert.Equal(t, "a", out.String(), "--template alone selects go-template")
+}
+
+func TestCustomColumnsPrinter(t *testing.T) {
+	printer, err := newCustomColumnsPrinter("NAME:metadata.name,TYPES:{.spec.limits[*].type},TEAM:.metadata.labels.team")
+	assert.NoError(t, err)
+
+	list := &v1.LimitRangeList{Items: []v1.LimitRange{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"team": "payments"}},
+			Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{
+				{Type: v1.LimitTypeContainer}, {Type: v1.LimitTypePod},
+			}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	}}
+	var out bytes.Buffer
+	assert.NoError(t, printer.PrintObj(list, &out))
+	assert.Equal(t,
+		"NAME   TYPES           TEAM\n"+
+			"a      Container,Pod   payments\n"+
+			"b      <none>          <none>\n",
+		out.String())
+}
+
+func TestNewCustomColumnsPrinterErrors(t *testing.T) {
+	tests := []struct {
+		spec        string
+		expectedErr string
+	}{
+		{spec: "", expectedErr: "custom-columns format specified but no custom columns given"},
+		{spec: "NAME", expectedErr: "unexpected custom-columns spec: NAME, expected <header>:<json-path-expr>"},
+		{spec: "NAME:.metadata.name,:.kind", expectedErr: "unexpected custom-columns spec: :.kind, expected <header>:<json-path-expr>"},
+	}
+	for _, tt := range tests {
+		_, err := newCustomColumnsPrinter(tt.spec)
+		assert.EqualError(t, err, tt.expectedErr, tt.spec)
+	}
+
+	_, err := newCustomColumnsPrinter("NAME:.metadata[")
+	assert.ErrorContains(t, err, "failed to parse custom column NAME")
+}
@@ -0,0 +1,496 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/rest"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+var (
+	recommendExample = `
+    # Suggest container limits from the requests and limits declared by the pods of a namespace
+    kubectl lr recommend my-limitrange --namespace=my-namespace
+
+    # Suggest limits offline from a directory of manifests and create them
+    kubectl lr recommend --dir=./manifests | kubectl apply -f -
+
+    # Use stricter percentiles and include ephemeral storage
+    kubectl lr recommend --min-percentile=10 --max-percentile=90 --resources=cpu,memory,ephemeral-storage
+
+    # Suggest limits from the usage reported by metrics-server over 5 minutes, with 30% headroom
+    kubectl lr recommend --from-metrics --window=5m --headroom=30
+    `
+)
+
+// Sizes that usage based values are rounded up to
+const (
+	metricsCPUStep    = 10 // millicores
+	metricsMemoryStep = 1 << 20
+)
+
+// resourceSamples holds the declared requests and limits of one resource across containers
+type resourceSamples struct {
+	requests []resource.Quantity
+	limits   []resource.Quantity
+}
+
+// RecommendOptions holds information required to recommend LimitRange values
+type RecommendOptions struct {
+	*LimitOptions
+	directory                string
+	resources                []string
+	minPercentile            float64
+	defaultRequestPercentile float64
+	defaultPercentile        float64
+	maxPercentile            float64
+	fromMetrics              bool
+	window                   time.Duration
+	interval                 time.Duration
+	headroom                 int
+	metricsClientFunc        func(*rest.Config) (metricsclientset.Interface, error)
+	sleep                    func(time.Duration)
+}
+
+// NewRecommendOptions initializes an instance of RecommendOptions with default values
+func NewRecommendOptions(streams genericiooptions.IOStreams) *RecommendOptions {
+	o := &RecommendOptions{
+		LimitOptions:             NewLimitOptions(streams),
+		resources:                []string{string(v1.ResourceCPU), string(v1.ResourceMemory)},
+		minPercentile:            5,
+		defaultRequestPercentile: 50,
+		defaultPercentile:        50,
+		maxPercentile:            95,
+		window:                   time.Minute,
+		interval:                 15 * time.Second,
+		headroom:                 20,
+		metricsClientFunc: func(config *rest.Config) (metricsclientset.Interface, error) {
+			return metricsclientset.NewForConfig(config)
+		},
+		sleep: time.Sleep,
+	}
+	o.name = "recommended"
+	o.output = "yaml"
+	return o
+}
+
+// NewCmdRecommend creates a cobra command suggesting LimitRange values from the resources workloads declare
+func NewCmdRecommend(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewRecommendOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "recommend [NAME] [flags]",
+		Short: "Suggest LimitRange values from the requests and limits of existing workloads",
+		Long: "Collect the requests and limits declared by the containers of the pods in a namespace, or of the " +
+			"workloads in a directory of manifests with --dir, and print a LimitRange whose container min, " +
+			"defaultRequest, default and max are percentiles of those values. With --from-metrics, the " +
+			"container usage reported by the metrics.k8s.io API is sampled instead, and defaultRequest, " +
+			"default and max are percentiles of that usage plus headroom.",
+		Example:      recommendExample,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				o.name = args[0]
+			}
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Validate(); err != nil {
+				return fmt.Errorf("validation error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.configFlags.AddFlags(cmd.Flags())
+	if nsFlag := cmd.Flag("namespace"); nsFlag != nil {
+		nsFlag.Shorthand = "n"
+	}
+	cmd.Flags().StringVar(&o.directory, "dir", "", "Directory of YAML or JSON manifests to read the workloads from instead of the cluster")
+	cmd.Flags().StringSliceVar(&o.resources, "resources", o.resources, "Resources to recommend values for")
+	cmd.Flags().Float64Var(&o.minPercentile, "min-percentile", o.minPercentile, "Percentile of the declared requests to use as min")
+	cmd.Flags().Float64Var(&o.defaultRequestPercentile, "default-request-percentile", o.defaultRequestPercentile, "Percentile of the declared requests to use as defaultRequest")
+	cmd.Flags().Float64Var(&o.defaultPercentile, "default-percentile", o.defaultPercentile, "Percentile of the declared limits to use as default")
+	cmd.Flags().Float64Var(&o.maxPercentile, "max-percentile", o.maxPercentile, "Percentile of the declared limits to use as max")
+	cmd.Flags().BoolVar(&o.fromMetrics, "from-metrics", o.fromMetrics, "Recommend from the container usage reported by the metrics.k8s.io API instead of the declared resources")
+	cmd.Flags().DurationVar(&o.window, "window", o.window, "How long to sample usage for with --from-metrics; 0 takes a single sample")
+	cmd.Flags().DurationVar(&o.interval, "interval", o.interval, "Time between usage samples with --from-metrics")
+	cmd.Flags().IntVar(&o.headroom, "headroom", o.headroom, "Percentage added on top of the observed usage with --from-metrics")
+	addOutputFlags(cmd, o.printFlags, &o.output)
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Complete sets the namespace and, with --from-metrics, the percentiles of the usage that
+// default and max are taken from unless given explicitly
+func (o *RecommendOptions) Complete(cmd *cobra.Command, args []string) error {
+	if err := o.LimitOptions.Complete(cmd, args); err != nil {
+		return err
+	}
+	if o.fromMetrics {
+		if !cmd.Flags().Changed("default-percentile") {
+			o.defaultPercentile = 95
+		}
+		if !cmd.Flags().Changed("max-percentile") {
+			o.maxPercentile = 100
+		}
+	}
+	return nil
+}
+
+// Validate checks the percentiles, resources and output format
+func (o *RecommendOptions) Validate() error {
+	if o.name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if o.namespace == "" {
+		return fmt.Errorf("namespace cannot be empty")
+	}
+	if len(o.resources) == 0 {
+		return fmt.Errorf("at least one resource must be given with --resources")
+	}
+	percentiles := []struct {
+		flag  string
+		value float64
+	}{
+		{"min-percentile", o.minPercentile},
+		{"default-request-percentile", o.defaultRequestPercentile},
+		{"default-percentile", o.defaultPercentile},
+		{"max-percentile", o.maxPercentile},
+	}
+	for _, p := range percentiles {
+		if p.value < 0 || p.value > 100 {
+			return fmt.Errorf("invalid --%s value %g: must be between 0 and 100", p.flag, p.value)
+		}
+	}
+	if o.fromMetrics {
+		if o.directory != "" {
+			return fmt.Errorf("--from-metrics cannot be combined with --dir")
+		}
+		for _, name := range o.resources {
+			if name != string(v1.ResourceCPU) && name != string(v1.ResourceMemory) {
+				return fmt.Errorf("resource %s is not reported by the metrics API, only cpu and memory are", name)
+			}
+		}
+		if o.window < 0 {
+			return fmt.Errorf("--window cannot be negative")
+		}
+		if o.interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		if o.headroom < 0 {
+			return fmt.Errorf("--headroom cannot be negative")
+		}
+	}
+	if _, err := toPrinter(o.printFlags, o.output); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Run computes the recommended values and prints them as a LimitRange
+func (o *RecommendOptions) Run() error {
+	var item v1.LimitRangeItem
+	var summary string
+	var err error
+	if o.fromMetrics {
+		item, summary, err = o.recommendFromMetrics()
+	} else {
+		item, summary, err = o.recommendFromSpecs()
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.IOStreams.ErrOut, summary)
+
+	limitRange := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: o.name, Namespace: o.namespace},
+		Spec:       v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{item}},
+	}
+	// Percentiles of odd samples, headroom and rounding can still give values the API server rejects
+	if err := o.validateLimitRange(limitRange); err != nil {
+		return fmt.Errorf("recommended LimitRange is not valid: %w", err)
+	}
+	return o.printOutputWithTypeMeta(limitRange, "")
+}
+
+// recommendFromSpecs computes the item from the resources declared by the pods of the
+// namespace or the workloads of --dir
+func (o *RecommendOptions) recommendFromSpecs() (v1.LimitRangeItem, string, error) {
+	var specs []*v1.PodSpec
+	var source string
+	var err error
+	if o.directory != "" {
+		specs, err = o.manifestPodSpecs()
+		source = fmt.Sprintf("the manifests in %s", o.directory)
+	} else {
+		specs, err = o.clusterPodSpecs()
+		source = fmt.Sprintf("the pods in namespace %s", o.namespace)
+	}
+	if err != nil {
+		return v1.LimitRangeItem{}, "", err
+	}
+
+	samples, containers := declaredResources(specs, o.resources)
+	item := o.recommendItem(samples)
+	if len(itemResourceNames(item)) == 0 {
+		return item, "", fmt.Errorf("no container in %s declares requests or limits for %s", source, strings.Join(o.resources, ", "))
+	}
+	return item, fmt.Sprintf("Recommended from %d containers in %s", containers, source), nil
+}
+
+// recommendFromMetrics computes the item from container usage sampled from the metrics API
+func (o *RecommendOptions) recommendFromMetrics() (v1.LimitRangeItem, string, error) {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return v1.LimitRangeItem{}, "", fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	metricsClient, err := o.metricsClientFunc(config)
+	if err != nil {
+		return v1.LimitRangeItem{}, "", fmt.Errorf("failed to create metrics client: %w", err)
+	}
+
+	usage, containers, err := o.sampleUsage(metricsClient)
+	if err != nil {
+		return v1.LimitRangeItem{}, "", err
+	}
+	item := o.usageItem(usage)
+	if len(itemResourceNames(item)) == 0 {
+		return item, "", fmt.Errorf("no pod metrics found in namespace %s", o.namespace)
+	}
+	return item, fmt.Sprintf("Recommended from %d usage samples of %d containers in namespace %s",
+		len(usage[v1.ResourceName(o.resources[0])]), containers, o.namespace), nil
+}
+
+// clusterPodSpecs returns the pod specs of the active pods in the namespace
+func (o *RecommendOptions) clusterPodSpecs() ([]*v1.PodSpec, error) {
+	config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+
+	clientset, err := o.clientsetFunc(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(o.namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	var specs []*v1.PodSpec
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == v1.PodSucceeded || pods.Items[i].Status.Phase == v1.PodFailed {
+			continue
+		}
+		specs = append(specs, &pods.Items[i].Spec)
+	}
+	return specs, nil
+}
+
+// manifestPodSpecs returns the pod specs of the workloads in the YAML and JSON files under --dir.
+// Objects of other kinds, such as Services or ConfigMaps, are skipped.
+func (o *RecommendOptions) manifestPodSpecs() ([]*v1.PodSpec, error) {
+	var specs []*v1.PodSpec
+	err := filepath.WalkDir(o.directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		objects, err := readObjects(file, true)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest %s: %w", path, err)
+		}
+		for _, object := range objects {
+			if _, spec, err := podSpecOf(object); err == nil {
+				specs = append(specs, spec)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// declaredResources collects the requests and limits of names across the containers of specs,
+// after defaulting missing requests to the limits the way the API server does. It also returns
+// the number of containers that were looked at.
+func declaredResources(specs []*v1.PodSpec, names []string) (map[v1.ResourceName]*resourceSamples, int) {
+	samples := map[v1.ResourceName]*resourceSamples{}
+	for _, name := range names {
+		samples[v1.ResourceName(name)] = &resourceSamples{}
+	}
+
+	containers := 0
+	for _, spec := range specs {
+		spec = spec.DeepCopy()
+		applyContainerDefaults(spec, nil)
+		for _, container := range podContainers(spec) {
+			containers++
+			for name, sample := range samples {
+				if request, ok := container.Resources.Requests[name]; ok {
+					sample.requests = append(sample.requests, request)
+				}
+				if limit, ok := container.Resources.Limits[name]; ok {
+					sample.limits = append(sample.limits, limit)
+				}
+			}
+		}
+	}
+	return samples, containers
+}
+
+// recommendItem builds a Container item from the percentiles of samples. Values are raised
+// where needed so that min <= defaultRequest <= default <= max holds.
+func (o *RecommendOptions) recommendItem(samples map[v1.ResourceName]*resourceSamples) v1.LimitRangeItem {
+	item := v1.LimitRangeItem{
+		Type:           v1.LimitTypeContainer,
+		Min:            v1.ResourceList{},
+		Max:            v1.ResourceList{},
+		Default:        v1.ResourceList{},
+		DefaultRequest: v1.ResourceList{},
+	}
+	for name, sample := range samples {
+		if len(sample.requests) > 0 {
+			item.Min[name] = percentile(sample.requests, o.minPercentile)
+			item.DefaultRequest[name] = percentile(sample.requests, o.defaultRequestPercentile)
+		}
+		if len(sample.limits) > 0 {
+			item.Default[name] = percentile(sample.limits, o.defaultPercentile)
+			item.Max[name] = percentile(sample.limits, o.maxPercentile)
+		}
+
+		orderItemValues(item, name)
+	}
+	return item
+}
+
+// orderItemValues raises each value of name in item to the one before it in the
+// ordering min <= defaultRequest <= default <= max
+func orderItemValues(item v1.LimitRangeItem, name v1.ResourceName) {
+	ordered := []v1.ResourceList{item.Min, item.DefaultRequest, item.Default, item.Max}
+	for i := 1; i < len(ordered); i++ {
+		previous, hasPrevious := ordered[i-1][name]
+		current, hasCurrent := ordered[i][name]
+		if hasPrevious && hasCurrent && current.Cmp(previous) < 0 {
+			ordered[i][name] = previous.DeepCopy()
+		}
+	}
+}
+
+// sampleUsage lists the pod metrics of the namespace once every --interval over --window
+// and returns the usage of every container in every sample, along with the number of
+// distinct containers seen
+func (o *RecommendOptions) sampleUsage(metricsClient metricsclientset.Interface) (map[v1.ResourceName][]resource.Quantity, int, error) {
+	usage := map[v1.ResourceName][]resource.Quantity{}
+	containers := map[string]bool{}
+
+	samples := 1 + int(o.window/o.interval)
+	fmt.Fprintf(o.IOStreams.ErrOut, "Sampling pod metrics in namespace %s %d times over %s\n", o.namespace, samples, o.window)
+	for i := 0; i < samples; i++ {
+		if i > 0 {
+			o.sleep(o.interval)
+		}
+		podMetrics, err := metricsClient.MetricsV1beta1().PodMetricses(o.namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list pod metrics: %w", err)
+		}
+		for _, pod := range podMetrics.Items {
+			for _, container := range pod.Containers {
+				containers[pod.Name+"/"+container.Name] = true
+				for _, name := range o.resources {
+					if quantity, ok := container.Usage[v1.ResourceName(name)]; ok {
+						usage[v1.ResourceName(name)] = append(usage[v1.ResourceName(name)], quantity)
+					}
+				}
+			}
+		}
+	}
+	return usage, len(containers), nil
+}
+
+// usageItem builds a Container item whose defaultRequest, default and max are percentiles
+// of the usage plus --headroom
+func (o *RecommendOptions) usageItem(usage map[v1.ResourceName][]resource.Quantity) v1.LimitRangeItem {
+	item := v1.LimitRangeItem{
+		Type:           v1.LimitTypeContainer,
+		Max:            v1.ResourceList{},
+		Default:        v1.ResourceList{},
+		DefaultRequest: v1.ResourceList{},
+	}
+	for name, quantities := range usage {
+		if len(quantities) == 0 {
+			continue
+		}
+		item.DefaultRequest[name] = withHeadroom(name, percentile(quantities, o.defaultRequestPercentile), o.headroom)
+		item.Default[name] = withHeadroom(name, percentile(quantities, o.defaultPercentile), o.headroom)
+		item.Max[name] = withHeadroom(name, percentile(quantities, o.maxPercentile), o.headroom)
+		orderItemValues(item, name)
+	}
+	return item
+}
+
+// withHeadroom adds headroom percent to quantity and rounds it up to 10 millicores for
+// cpu or to a mebibyte for memory, so that the values are readable. Zero usage is
+// raised to a single step, as a LimitRange default of zero is of no use.
+func withHeadroom(name v1.ResourceName, quantity resource.Quantity, headroom int) resource.Quantity {
+	if name == v1.ResourceCPU {
+		value := roundUp(quantity.MilliValue(), headroom, metricsCPUStep)
+		return *resource.NewMilliQuantity(value, resource.DecimalSI)
+	}
+	value := roundUp(quantity.Value(), headroom, metricsMemoryStep)
+	return *resource.NewQuantity(value, resource.BinarySI)
+}
+
+// roundUp adds headroom percent to value and rounds the result up to a multiple of step
+func roundUp(value int64, headroom int, step int64) int64 {
+	value = int64(math.Ceil(float64(value) * float64(100+headroom) / 100))
+	steps := max((value+step-1)/step, 1)
+	return steps * step
+}
+
+// percentile returns the nearest-rank percentile p of quantities
+func percentile(quantities []resource.Quantity, p float64) resource.Quantity {
+	sorted := make([]resource.Quantity, len(quantities))
+	copy(sorted, quantities)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	index := min(max(rank-1, 0), len(sorted)-1)
+	return sorted[index].DeepCopy()
+}
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func newRecommendOptions(fakeClientset kubernetes.Interface) *RecommendOptions {
+	o := NewRecommendOptions(genericclioptions.IOStreams{
+		Out:    new(bytes.Buffer),
+		ErrOut: new(bytes.Buffer),
+	})
+	o.namespace = "default"
+	o.configFlags, o.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return o
+}
+
+func declaredPod(name string, phase v1.PodPhase, requests, limits v1.ResourceList) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name:      "app",
+			Resources: v1.ResourceRequirements{Requests: requests, Limits: limits},
+		}}},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestRunRecommendFromPods(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(
+		declaredPod("a", v1.PodRunning, resources("cpu", "100m", "memory", "64Mi"), resources("cpu", "200m", "memory", "128Mi")),
+		declaredPod("b", v1.PodRunning, resources("cpu", "250m", "memory", "128Mi"), resources("cpu", "500m", "memory", "256Mi")),
+		declaredPod("c", v1.PodPending, nil, resources("cpu", "1", "memory", "1Gi")),
+		declaredPod("done", v1.PodSucceeded, nil, resources("cpu", "8", "memory", "8Gi")),
+	)
+	options := newRecommendOptions(fakeClientset)
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, `apiVersion: v1
+kind: LimitRange
+metadata:
+  creationTimestamp: null
+  name: recommended
+  namespace: default
+spec:
+  limits:
+  - default:
+      cpu: 500m
+      memory: 256Mi
+    defaultRequest:
+      cpu: 250m
+      memory: 128Mi
+    max:
+      cpu: "1"
+      memory: 1Gi
+    min:
+      cpu: 100m
+      memory: 64Mi
+    type: Container
+`, options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "Recommended from 3 containers in the pods in namespace default\n",
+		options.IOStreams.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestRunRecommendFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "base"), 0o755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "chart"), 0o755))
+	files := map[string]string{
+		"base/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels: {app: web}
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers:
+      - name: web
+        image: web
+        resources:
+          requests: {cpu: 200m}
+          limits: {cpu: 400m}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`,
+		"base/kustomization.yaml": `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`,
+		"job.json": `{"apiVersion": "batch/v1", "kind": "Job", "metadata": {"name": "report"},
+"spec": {"template": {"spec": {"restartPolicy": "Never", "containers": [{"name": "report", "image": "report",
+"resources": {"limits": {"cpu": "2"}}}]}}}}`,
+		"chart/values.yaml": `
+replicaCount: 2
+resources:
+  limits: {cpu: 8}
+`,
+		"chart/hosts.yaml": "- web.example.com\n- api.example.com\n",
+		"README.md":        "not a manifest",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	options := newRecommendOptions(nil)
+	options.directory = dir
+	options.output = "json"
+	options.minPercentile = 0
+	options.maxPercentile = 100
+
+	assert.NoError(t, options.Run())
+	assert.JSONEq(t, `{
+		"apiVersion": "v1",
+		"kind": "LimitRange",
+		"metadata": {"name": "recommended", "namespace": "default", "creationTimestamp": null},
+		"spec": {"limits": [{
+			"type": "Container",
+			"min": {"cpu": "200m"},
+			"max": {"cpu": "2"},
+			"default": {"cpu": "400m"},
+			"defaultRequest": {"cpu": "200m"}
+		}]}
+	}`, options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "Recommended from 2 containers in the manifests in "+dir+"\n",
+		options.IOStreams.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestRunRecommendNoSamples(t *testing.T) {
+	options := newRecommendOptions(fake.NewSimpleClientset(declaredPod("a", v1.PodRunning, nil, nil)))
+
+	err := options.Run()
+	assert.EqualError(t, err, "no container in the pods in namespace default declares requests or limits for cpu, memory")
+}
+
+func TestRunRecommendRejectsInvalidLimitRange(t *testing.T) {
+	options := newRecommendOptions(fake.NewSimpleClientset(
+		declaredPod("a", v1.PodRunning, resources("cpu", "0"), resources("cpu", "1")),
+	))
+	options.resources = []string{"cpu"}
+
+	err := options.Run()
+	assert.EqualError(t, err, "recommended LimitRange is not valid: invalid limits[0].min.cpu value: must be greater than zero")
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+}
+
+// newMetricsRecommendOptions returns options in --from-metrics mode whose metrics client
+// answers each list with the next of samples
+func newMetricsRecommendOptions(samples ...[]metricsv1beta1.PodMetrics) (*RecommendOptions, *[]time.Duration) {
+	metricsClient := metricsfake.NewSimpleClientset()
+	calls := 0
+	metricsClient.PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		if calls >= len(samples) {
+			return true, nil, fmt.Errorf("metrics API unavailable")
+		}
+		calls++
+		return true, &metricsv1beta1.PodMetricsList{Items: samples[calls-1]}, nil
+	})
+
+	var sleeps []time.Duration
+	options := newRecommendOptions(nil)
+	options.fromMetrics = true
+	options.defaultPercentile = 95
+	options.maxPercentile = 100
+	options.metricsClientFunc = func(_ *rest.Config) (metricsclientset.Interface, error) {
+		return metricsClient, nil
+	}
+	options.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return options, &sleeps
+}
+
+func podMetrics(name string, usage ...v1.ResourceList) metricsv1beta1.PodMetrics {
+	metrics := metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	for i, containerUsage := range usage {
+		metrics.Containers = append(metrics.Containers, metricsv1beta1.ContainerMetrics{
+			Name:  fmt.Sprintf("c%d", i),
+			Usage: containerUsage,
+		})
+	}
+	return metrics
+}
+
+func TestRunRecommendFromMetrics(t *testing.T) {
+	options, sleeps := newMetricsRecommendOptions(
+		[]metricsv1beta1.PodMetrics{
+			podMetrics("a", resources("cpu", "100m", "memory", "100Mi"), resources("cpu", "12345678n", "memory", "20Mi")),
+		},
+		[]metricsv1beta1.PodMetrics{
+			podMetrics("a", resources("cpu", "150m", "memory", "110Mi"), resources("cpu", "0", "memory", "21Mi")),
+			podMetrics("b", resources("cpu", "400m", "memory", "300Mi")),
+		},
+		[]metricsv1beta1.PodMetrics{
+			podMetrics("a", resources("cpu", "120m", "memory", "105Mi"), resources("cpu", "5m", "memory", "20Mi")),
+		},
+	)
+	options.window = 30 * time.Second
+	options.interval = 15 * time.Second
+
+	assert.NoError(t, options.Run())
+	assert.Equal(t, []time.Duration{15 * time.Second, 15 * time.Second}, *sleeps)
+	assert.Equal(t, `apiVersion: v1
+kind: LimitRange
+metadata:
+  creationTimestamp: null
+  name: recommended
+  namespace: default
+spec:
+  limits:
+  - default:
+      cpu: 480m
+      memory: 360Mi
+    defaultRequest:
+      cpu: 120m
+      memory: 120Mi
+    max:
+      cpu: 480m
+      memory: 360Mi
+    type: Container
+`, options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Equal(t, "Sampling pod metrics in namespace default 3 times over 30s\n"+
+		"Recommended from 7 usage samples of 3 containers in namespace default\n",
+		options.IOStreams.ErrOut.(*bytes.Buffer).String())
+}
+
+func TestRunRecommendFromMetricsErrors(t *testing.T) {
+	options, _ := newMetricsRecommendOptions()
+	options.window = 0
+	assert.EqualError(t, options.Run(), "failed to list pod metrics: metrics API unavailable")
+
+	options, _ = newMetricsRecommendOptions([]metricsv1beta1.PodMetrics{})
+	options.window = 0
+	assert.EqualError(t, options.Run(), "no pod metrics found in namespace default")
+}
+
+func TestWithHeadroom(t *testing.T) {
+	tests := []struct {
+		name     v1.ResourceName
+		quantity string
+		headroom int
+		expected string
+	}{
+		{v1.ResourceCPU, "100m", 20, "120m"},
+		{v1.ResourceCPU, "12345678n", 20, "20m"},
+		{v1.ResourceCPU, "0", 20, "10m"},
+		{v1.ResourceCPU, "1", 0, "1"},
+		{v1.ResourceMemory, "100Mi", 20, "120Mi"},
+		{v1.ResourceMemory, "1000000", 0, "1Mi"},
+		{v1.ResourceMemory, "1Gi", 50, "1536Mi"},
+	}
+	for _, tt := range tests {
+		got := withHeadroom(tt.name, resource.MustParse(tt.quantity), tt.headroom)
+		assert.Equal(t, tt.expected, got.String(), "%s %s with %d%% headroom", tt.name, tt.quantity, tt.headroom)
+	}
+}
+
+func TestRecommendItemOrdering(t *testing.T) {
+	options := NewRecommendOptions(genericclioptions.IOStreams{})
+	options.minPercentile = 100
+	options.maxPercentile = 0
+
+	item := options.recommendItem(map[v1.ResourceName]*resourceSamples{
+		v1.ResourceCPU: {
+			requests: []resource.Quantity{resource.MustParse("100m"), resource.MustParse("1")},
+			limits:   []resource.Quantity{resource.MustParse("500m"), resource.MustParse("2")},
+		},
+	})
+	assert.Equal(t, "1", item.Min.Cpu().String())
+	assert.Equal(t, "1", item.DefaultRequest.Cpu().String(), "raised to min")
+	assert.Equal(t, "1", item.Default.Cpu().String(), "raised to defaultRequest")
+	assert.Equal(t, "1", item.Max.Cpu().String(), "raised to default")
+}
+
+func TestPercentile(t *testing.T) {
+	quantities := []resource.Quantity{
+		resource.MustParse("4"), resource.MustParse("1"), resource.MustParse("3"), resource.MustParse("2000m"),
+	}
+	tests := []struct {
+		percentile float64
+		expected   string
+	}{
+		{0, "1"},
+		{25, "1"},
+		{50, "2"},
+		{75, "3"},
+		{95, "4"},
+		{100, "4"},
+	}
+	for _, tt := range tests {
+		got := percentile(quantities, tt.percentile)
+		assert.Equal(t, tt.expected, got.String(), "percentile %g", tt.percentile)
+	}
+	assert.Equal(t, "4", quantities[0].String(), "input is not reordered")
+}
+
+func TestRecommendValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*RecommendOptions)
+		expectedErr string
+	}{
+		{name: "defaults", modify: func(_ *RecommendOptions) {}},
+		{name: "no resources", modify: func(o *RecommendOptions) { o.resources = nil },
+			expectedErr: "at least one resource must be given with --resources"},
+		{name: "percentile out of range", modify: func(o *RecommendOptions) { o.maxPercentile = 101 },
+			expectedErr: "invalid --max-percentile value 101: must be between 0 and 100"},
+		{name: "negative percentile", modify: func(o *RecommendOptions) { o.minPercentile = -1 },
+			expectedErr: "invalid --min-percentile value -1: must be between 0 and 100"},
+		{name: "output", modify: func(o *RecommendOptions) { o.output = "table" },
+			expectedErr: `unable to match a printer suitable for the output format "table", allowed formats are: ` +
+				"go-template,go-template-file,json,jsonpath,jsonpath-as-json,jsonpath-file,name,template,templatefile,yaml"},
+		{name: "empty namespace", modify: func(o *RecommendOptions) { o.namespace = "" },
+			expectedErr: "namespace cannot be empty"},
+		{name: "metrics", modify: func(o *RecommendOptions) { o.fromMetrics = true }},
+		{name: "metrics with directory", modify: func(o *RecommendOptions) { o.fromMetrics, o.directory = true, "manifests" },
+			expectedErr: "--from-metrics cannot be combined with --dir"},
+		{name: "metrics resource", modify: func(o *RecommendOptions) {
+			o.fromMetrics, o.resources = true, []string{"cpu", "ephemeral-storage"}
+		}, expectedErr: "resource ephemeral-storage is not reported by the metrics API, only cpu and memory are"},
+		{name: "negative window", modify: func(o *RecommendOptions) { o.fromMetrics, o.window = true, -time.Second },
+			expectedErr: "--window cannot be negative"},
+		{name: "zero interval", modify: func(o *RecommendOptions) { o.fromMetrics, o.interval = true, 0 },
+			expectedErr: "--interval must be positive"},
+		{name: "negative headroom", modify: func(o *RecommendOptions) { o.fromMetrics, o.headroom = true, -5 },
+			expectedErr: "--headroom cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := newRecommendOptions(nil)
+			tt.modify(options)
+			err := options.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+// NewCmdLR creates the root command of the kubectl lr plugin. It groups create with the commands
+// that inspect and manage LimitRanges, so that none of them can be mistaken for the NAME of
+// the LimitRange to create.
+func NewCmdLR(streams genericiooptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lr",
+		Short:        "Create, inspect and manage LimitRange resources",
+		SilenceUsage: true,
+		// Show usage as kubectl runs the plugin
+		Annotations: map[string]string{cobra.CommandDisplayNameAnnotation: "kubectl lr"},
+	}
+
+	// coverage:ignore-start
+	create := NewCmdCreateLimitRange(streams)
+	create.Use = "create NAME | -f FILENAME [flags]"
+	create.Example = strings.ReplaceAll(create.Example, "kubectl create limitrange", "kubectl lr create")
+
+	cmd.AddCommand(create)
+	cmd.AddCommand(NewCmdPresets(streams))
+	cmd.AddCommand(NewCmdDiff(streams))
+	cmd.AddCommand(NewCmdGet(streams))
+	cmd.AddCommand(NewCmdDescribe(streams))
+	cmd.AddCommand(NewCmdDelete(streams))
+	cmd.AddCommand(NewCmdEdit(streams))
+	cmd.AddCommand(NewCmdAudit(streams))
+	cmd.AddCommand(NewCmdSimulate(streams))
+	cmd.AddCommand(NewCmdRecommend(streams))
+
+	return cmd
+	// coverage:ignore-end
+}
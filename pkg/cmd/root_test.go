@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestCreateLimitRangeNamedAfterCommand(t *testing.T) {
+	// Names of the lr commands, and those cobra adds to commands with children
+	for _, name := range []string{"get", "delete", "presets", "help", "completion"} {
+		t.Run(name, func(t *testing.T) {
+			for _, newCmd := range []struct {
+				cmd  func(genericiooptions.IOStreams) *cobra.Command
+				args []string
+			}{
+				{NewCmdCreateLimitRange, []string{name}},
+				{NewCmdLR, []string{"create", name}},
+			} {
+				out := new(bytes.Buffer)
+				cmd := newCmd.cmd(genericiooptions.IOStreams{Out: out, ErrOut: new(bytes.Buffer)})
+				cmd.SetArgs(append(newCmd.args, "--namespace=default", "--max-cpu=1", "--dry-run=client", "-o", "name"))
+
+				assert.NoError(t, cmd.Execute())
+				assert.Equal(t, "limitrange/"+name+"\n", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var (
+	simulateExample = `
+    # Check how a pod would be defaulted and validated by a proposed LimitRange, without a cluster
+    kubectl lr simulate -m pod.yaml --max-cpu=1 --default-request-cpu=100m
+
+    # Check the pods of a Deployment against the LimitRanges currently in its namespace
+    kubectl lr simulate -m deployment.yaml --live
+
+    # Read the manifest from stdin
+    helm template my-chart | kubectl lr simulate -m - --preset=small
+    `
+)
+
+// SimulateOptions holds information required to simulate LimitRanger admission on a manifest
+type SimulateOptions struct {
+	*LimitOptions
+	manifest string
+	live     bool
+}
+
+// NewCmdSimulate creates a cobra command applying LimitRanger defaulting and validation to a manifest
+func NewCmdSimulate(streams genericiooptions.IOStreams) *cobra.Command {
+	o := &SimulateOptions{LimitOptions: NewLimitOptions(streams)}
+	o.output = "yaml"
+
+	cmd := &cobra.Command{
+		Use:   "simulate -m MANIFEST [NAME] [flags]",
+		Short: "Show how the LimitRanger admission plugin would treat the pods of a manifest",
+		Long: "Apply the defaults and checks of the LimitRanger admission plugin to the Pods, Deployments, StatefulSets, " +
+			"DaemonSets, ReplicaSets, Jobs and CronJobs of a manifest. The LimitRange is built from the same flags, preset " +
+			"or spec file as create, or with --live taken from the namespace. Admitted objects are printed with their " +
+			"pod spec defaulted; rejected ones report the errors the API server would return.",
+		Example:      simulateExample,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			o.nameProposedLimitRange(args)
+			if err := o.Complete(c, args); err != nil {
+				return fmt.Errorf("completion error: %w", err)
+			}
+			if err := o.Validate(); err != nil {
+				return fmt.Errorf("validation error: %w", err)
+			}
+			if err := o.Run(); err != nil {
+				return fmt.Errorf("execution error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	// coverage:ignore-start
+	o.addLimitFlags(cmd)
+	cmd.Flags().StringVarP(&o.manifest, "manifest", "m", "", "YAML or JSON manifest of the pods or workloads to check, or - to read from stdin")
+	cmd.Flags().BoolVar(&o.live, "live", false, "If true, use the LimitRanges of the namespace instead of building one from flags")
+	addOutputFlags(cmd, o.printFlags, &o.output)
+
+	return cmd
+	// coverage:ignore-end
+}
+
+// Validate checks that a manifest is given along with either a proposed LimitRange or --live
+func (o *SimulateOptions) Validate() error {
+	if o.manifest == "" {
+		return fmt.Errorf("--manifest is required")
+	}
+	if o.manifest == "-" && o.filename == "-" {
+		return fmt.Errorf("--manifest and --filename cannot both read from stdin")
+	}
+	if _, err := toPrinter(o.printFlags, o.output); err != nil {
+		return err
+	}
+	if !o.live {
+		return o.LimitOptions.Validate()
+	}
+
+	if _, err := o.genericResourceFlags(); err != nil {
+		return err
+	}
+	specified := len(o.specs) > 0 || o.preset != nil
+	for _, f := range o.resourceFlags() {
+		if f.value != "" {
+			specified = true
+		}
+	}
+	if specified {
+		return fmt.Errorf("--live cannot be combined with resource flags, --preset or --filename")
+	}
+	return nil
+}
+
+// Run admits every object of the manifest and prints the defaulted ones, reporting the rejected
+// ones on ErrOut. It fails when any object would be rejected.
+func (o *SimulateOptions) Run() error {
+	objects, err := o.readManifest()
+	if err != nil {
+		return err
+	}
+
+	var proposed []*v1.LimitRange
+	var clientset kubernetes.Interface
+	// With --live, the LimitRanges of each namespace are listed once and shared by its objects
+	live := map[string][]*v1.LimitRange{}
+	if o.live {
+		config, err := o.configFlags.ToRawKubeConfigLoader().ClientConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+		}
+
+		clientset, err = o.clientsetFunc(config)
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+		}
+	} else {
+		proposed = o.storedLimitRanges()
+	}
+
+	rejected, printed := 0, 0
+	for _, object := range objects {
+		podMeta, spec, err := podSpecOf(object)
+		if err != nil {
+			return err
+		}
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return err
+		}
+
+		limitRanges := proposed
+		if o.live {
+			namespace := accessor.GetNamespace()
+			if namespace == "" {
+				namespace = o.namespace
+			}
+			var listed bool
+			if limitRanges, listed = live[namespace]; !listed {
+				if limitRanges, err = listLimitRanges(clientset, namespace); err != nil {
+					return err
+				}
+				live[namespace] = limitRanges
+			}
+		}
+
+		if annotation := applyContainerDefaults(spec, limitRanges); annotation != "" {
+			if podMeta.Annotations == nil {
+				podMeta.Annotations = map[string]string{}
+			}
+			podMeta.Annotations[limitRangerAnnotation] = annotation
+		}
+
+		if violations := validatePodResources(spec, limitRanges); len(violations) > 0 {
+			rejected++
+			messages := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				messages = append(messages, violation.message)
+			}
+			reason := messages[0]
+			if len(messages) > 1 {
+				reason = "[" + strings.Join(messages, ", ") + "]"
+			}
+			if kind := object.GetObjectKind().GroupVersionKind().Kind; kind == "Pod" {
+				fmt.Fprintf(o.IOStreams.ErrOut, "pods %q is forbidden: %s\n", accessor.GetName(), reason)
+			} else {
+				fmt.Fprintf(o.IOStreams.ErrOut, "pods of %s %q would be forbidden: %s\n", strings.ToLower(kind), accessor.GetName(), reason)
+			}
+			continue
+		}
+
+		if printed > 0 && o.output == "yaml" {
+			fmt.Fprintln(o.IOStreams.Out, "---")
+		}
+		if err := printObject(o.IOStreams.Out, o.printFlags, o.output, object); err != nil {
+			return err
+		}
+		printed++
+	}
+
+	if rejected > 0 {
+		return fmt.Errorf("%d of %d objects would be rejected by the LimitRanger admission plugin", rejected, len(objects))
+	}
+	return nil
+}
+
+// listLimitRanges returns the LimitRanges of namespace, all of which the admission plugin applies
+func listLimitRanges(clientset kubernetes.Interface, namespace string) ([]*v1.LimitRange, error) {
+	list, err := clientset.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+	limitRanges := make([]*v1.LimitRange, 0, len(list.Items))
+	for i := range list.Items {
+		limitRanges = append(limitRanges, &list.Items[i])
+	}
+	return limitRanges, nil
+}
+
+// readManifest decodes the objects of --manifest, where - stands for stdin
+func (o *SimulateOptions) readManifest() ([]runtime.Object, error) {
+	var reader io.Reader
+	if o.manifest == "-" {
+		if o.IOStreams.In == nil {
+			return nil, fmt.Errorf("no input stream to read the manifest from")
+		}
+		reader = o.IOStreams.In
+	} else {
+		file, err := os.Open(o.manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open manifest: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	objects, err := readObjects(reader, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", o.manifest, err)
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("manifest %s does not contain any object", o.manifest)
+	}
+	return objects, nil
+}
+
+// readObjects decodes the Kubernetes objects of the YAML or JSON documents in r.
+// Lists, such as the output of kubectl get -o yaml, are expanded into their items. With
+// skipUnregistered, documents that are not objects of a kind known to the client scheme, such
+// as custom resources, kustomization files or Helm values files, are left out instead of
+// failing the read.
+func readObjects(r io.Reader, skipUnregistered bool) ([]runtime.Object, error) {
+	var objects []runtime.Object
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	deserializer := scheme.Codecs.UniversalDeserializer()
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 || string(raw) == "null" {
+			// Skip empty documents, e.g. a trailing ---
+			continue
+		}
+
+		if skipUnregistered && bytes.TrimSpace(raw)[0] != '{' {
+			// Scalars and sequences cannot carry a kind
+			continue
+		}
+		object, _, err := deserializer.Decode(raw, nil, nil)
+		if skipUnregistered && isUnknownObjectError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if list, ok := object.(*v1.List); ok {
+			for _, item := range list.Items {
+				itemObject, _, err := deserializer.Decode(item.Raw, nil, nil)
+				if skipUnregistered && isUnknownObjectError(err) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				objects = append(objects, itemObject)
+			}
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// isUnknownObjectError reports whether err means that a decoded document has no apiVersion or
+// kind, or one that the client scheme does not know
+func isUnknownObjectError(err error) bool {
+	return runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) || runtime.IsMissingVersion(err)
+}
+
+// podSpecOf returns the pod spec of object along with the metadata that receives the
+// LimitRanger annotation: those of the Pod itself, or of the pod template of a workload
+func podSpecOf(object runtime.Object) (*metav1.ObjectMeta, *v1.PodSpec, error) {
+	switch typed := object.(type) {
+	case *v1.Pod:
+		return &typed.ObjectMeta, &typed.Spec, nil
+	case *appsv1.Deployment:
+		return &typed.Spec.Template.ObjectMeta, &typed.Spec.Template.Spec, nil
+	case *appsv1.StatefulSet:
+		return &typed.Spec.Template.ObjectMeta, &typed.Spec.Template.Spec, nil
+	case *appsv1.DaemonSet:
+		return &typed.Spec.Template.ObjectMeta, &typed.Spec.Template.Spec, nil
+	case *appsv1.ReplicaSet:
+		return &typed.Spec.Template.ObjectMeta, &typed.Spec.Template.Spec, nil
+	case *batchv1.Job:
+		return &typed.Spec.Template.ObjectMeta, &typed.Spec.Template.Spec, nil
+	case *batchv1.CronJob:
+		return &typed.Spec.JobTemplate.Spec.Template.ObjectMeta, &typed.Spec.JobTemplate.Spec.Template.Spec, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported kind %s, must be one of Pod, Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob",
+			object.GetObjectKind().GroupVersionKind().Kind)
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const simulatedManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+    resources:
+      requests:
+        memory: 64Mi
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  selector:
+    matchLabels: {app: worker}
+  template:
+    metadata:
+      labels: {app: worker}
+    spec:
+      containers:
+      - name: worker
+        image: worker
+        resources:
+          limits:
+            cpu: "2"
+`
+
+func newSimulateOptions(manifest string, fakeClientset kubernetes.Interface) *SimulateOptions {
+	options := &LimitOptions{
+		name:      "proposed",
+		namespace: "default",
+		IOStreams: genericclioptions.IOStreams{
+			In:     strings.NewReader(manifest),
+			Out:    new(bytes.Buffer),
+			ErrOut: new(bytes.Buffer),
+		},
+		output: "yaml",
+	}
+	options.configFlags, options.clientsetFunc = fakeClientsetFlags(fakeClientset)
+	return &SimulateOptions{LimitOptions: options, manifest: "-"}
+}
+
+func TestRunSimulateFromFlags(t *testing.T) {
+	options := newSimulateOptions(simulatedManifest, nil)
+	options.maxCPU = "1"
+	options.defaultRequestCPU = "250m"
+
+	err := options.Run()
+	assert.EqualError(t, err, "1 of 2 objects would be rejected by the LimitRanger admission plugin")
+	assert.Equal(t, "pods of deployment \"worker\" would be forbidden: maximum cpu usage per Container is 1, but limit is 2\n",
+		options.IOStreams.ErrOut.(*bytes.Buffer).String())
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Contains(t, output, "kind: Pod\n")
+	assert.Contains(t, output, "kubernetes.io/limit-ranger: 'LimitRanger plugin set: cpu request for container\n      nginx; cpu limit for container nginx'\n")
+	assert.Contains(t, output, "      limits:\n        cpu: \"1\"\n      requests:\n        cpu: 250m\n        memory: 64Mi\n")
+	assert.NotContains(t, output, "worker")
+}
+
+func TestRunSimulateLive(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(&v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default"},
+		Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Max:            v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			},
+			{
+				Type: v1.LimitTypePod,
+				Max:  v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Mi")},
+			},
+		}},
+	})
+	options := newSimulateOptions(simulatedManifest, fakeClientset)
+	options.live = true
+	options.output = "json"
+
+	err := options.Run()
+	assert.EqualError(t, err, "2 of 2 objects would be rejected by the LimitRanger admission plugin")
+	assert.Equal(t,
+		"pods \"web\" is forbidden: maximum memory usage per Pod is 32Mi.  No limit is specified\n"+
+			"pods of deployment \"worker\" would be forbidden: maximum memory usage per Pod is 32Mi.  No limit is specified\n",
+		options.IOStreams.ErrOut.(*bytes.Buffer).String())
+	assert.Empty(t, options.IOStreams.Out.(*bytes.Buffer).String())
+	assert.Len(t, fakeClientset.Actions(), 1, "both objects share the LimitRanges listed for their namespace")
+}
+
+func TestRunSimulateManifestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pods.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata: {name: a}
+  spec:
+    containers: [{name: app, image: app}]
+- apiVersion: v1
+  kind: Pod
+  metadata: {name: b}
+  spec:
+    containers: [{name: app, image: app}]
+`), 0o600))
+	options := newSimulateOptions("", nil)
+	options.manifest = path
+	options.maxMemory = "1Gi"
+
+	assert.NoError(t, options.Run())
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Equal(t, 1, strings.Count(output, "---\n"))
+	assert.Equal(t, 4, strings.Count(output, "memory: 1Gi\n"), "both pods get the default limit and request")
+}
+
+func TestReadObjectsUnsupportedKind(t *testing.T) {
+	objects, err := readObjects(strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata: {name: a}\n"), false)
+	assert.NoError(t, err)
+	if assert.Len(t, objects, 1) {
+		_, _, err = podSpecOf(objects[0])
+		assert.EqualError(t, err, "unsupported kind ConfigMap, must be one of Pod, Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob")
+	}
+}
+
+func TestReadObjectsSkipUnregistered(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Pod
+metadata: {name: a}
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata: {name: b}
+---
+replicaCount: 2
+---
+kind: Pod
+metadata: {name: c}
+---
+- not
+- an object
+`
+	objects, err := readObjects(strings.NewReader(manifest), true)
+	assert.NoError(t, err)
+	if assert.Len(t, objects, 1) {
+		assert.Equal(t, "a", objects[0].(*v1.Pod).Name)
+	}
+
+	_, err = readObjects(strings.NewReader(manifest), false)
+	assert.ErrorContains(t, err, "no kind \"Widget\" is registered")
+}
+
+func TestSimulateValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*SimulateOptions)
+		expectedErr string
+	}{
+		{
+			name:   "proposed LimitRange",
+			modify: func(o *SimulateOptions) { o.maxCPU = "1" },
+		},
+		{
+			name:   "live",
+			modify: func(o *SimulateOptions) { o.live = true },
+		},
+		{
+			name:        "no manifest",
+			modify:      func(o *SimulateOptions) { o.manifest = ""; o.maxCPU = "1" },
+			expectedErr: "--manifest is required",
+		},
+		{
+			name:        "both from stdin",
+			modify:      func(o *SimulateOptions) { o.filename = "-" },
+			expectedErr: "--manifest and --filename cannot both read from stdin",
+		},
+		{
+			name:        "live with flags",
+			modify:      func(o *SimulateOptions) { o.live = true; o.maxCPU = "1" },
+			expectedErr: "--live cannot be combined with resource flags, --preset or --filename",
+		},
+		{
+			name:   "unsupported output",
+			modify: func(o *SimulateOptions) { o.output = "table"; o.maxCPU = "1" },
+			expectedErr: `unable to match a printer suitable for the output format "table", allowed formats are: ` +
+				"go-template,go-template-file,json,jsonpath,jsonpath-as-json,jsonpath-file,name,template,templatefile,yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := newSimulateOptions("", nil)
+			tt.modify(options)
+			err := options.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// limitRangeSpec is the compact description of a LimitRange accepted by --filename:
+//
+//	name: my-limitrange
+//	namespace: my-namespace # optional
+//	limits:
+//	- type: Container
+//	  max: {cpu: "1", memory: 1Gi}
+//	  defaultRequest: {cpu: 100m}
+type limitRangeSpec struct {
+	Name      string              `json:"name,omitempty"`
+	Namespace string              `json:"namespace,omitempty"`
+	Limits    []v1.LimitRangeItem `json:"limits,omitempty"`
+}
+
+// limitRangeSpecDocument is one YAML or JSON document of a spec file. It describes either
+// a single LimitRange or a list of them under limitRanges.
+type limitRangeSpecDocument struct {
+	limitRangeSpec
+	LimitRanges []limitRangeSpec `json:"limitRanges,omitempty"`
+}
+
+// loadSpecFile reads the LimitRanges described by --filename, where - stands for stdin
+func (o *LimitOptions) loadSpecFile() error {
+	var reader io.Reader
+	if o.filename == "-" {
+		if o.IOStreams.In == nil {
+			return fmt.Errorf("no input stream to read the spec from")
+		}
+		reader = o.IOStreams.In
+	} else {
+		file, err := os.Open(o.filename)
+		if err != nil {
+			return fmt.Errorf("failed to open spec file: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	specs, err := readLimitRangeSpecs(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read spec file %s: %w", o.filename, err)
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("spec file %s does not describe any LimitRange", o.filename)
+	}
+	o.specs = specs
+	return nil
+}
+
+// readLimitRangeSpecs decodes every LimitRange described by the YAML or JSON documents in r
+func readLimitRangeSpecs(r io.Reader) ([]limitRangeSpec, error) {
+	var specs []limitRangeSpec
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 || string(raw) == "null" {
+			// Skip empty documents, e.g. a trailing ---
+			continue
+		}
+
+		var document limitRangeSpecDocument
+		strict := json.NewDecoder(bytes.NewReader(raw))
+		strict.DisallowUnknownFields()
+		if err := strict.Decode(&document); err != nil {
+			return nil, err
+		}
+
+		if document.Name != "" || document.Namespace != "" || len(document.Limits) > 0 {
+			specs = append(specs, document.limitRangeSpec)
+		}
+		specs = append(specs, document.LimitRanges...)
+	}
+
+	for i := range specs {
+		normalizeLimitTypes(specs[i].Limits)
+	}
+	return specs, nil
+}
+
+// normalizeLimitTypes defaults empty item types to Container and resolves the short,
+// case-insensitive type names accepted by the generic flags, such as pod or pvc
+func normalizeLimitTypes(items []v1.LimitRangeItem) {
+	for i := range items {
+		if items[i].Type == "" {
+			items[i].Type = v1.LimitTypeContainer
+		} else if limitType, ok := limitTypeNames[strings.ToLower(string(items[i].Type))]; ok {
+			items[i].Type = limitType
+		}
+	}
+}
+
+// toLimitRange returns a LimitRange object holding a copy of the spec's values
+func (s limitRangeSpec) toLimitRange() *v1.LimitRange {
+	limitRange := &v1.LimitRange{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "LimitRange",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.Name,
+			Namespace: s.Namespace,
+		},
+	}
+	for _, item := range s.Limits {
+		limitRange.Spec.Limits = append(limitRange.Spec.Limits, *item.DeepCopy())
+	}
+	return limitRange
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const testSpecFile = `
+name: team-limits
+namespace: team-a
+limits:
+- type: Container
+  max:
+    cpu: 1
+    memory: 1Gi
+  defaultRequest:
+    cpu: 100m
+- type: pvc
+  max:
+    storage: 10Gi
+---
+limitRanges:
+- name: batch-limits
+  limits:
+  - max:
+      cpu: "4"
+- name: pod-limits
+  limits:
+  - type: Pod
+    max:
+      memory: 8Gi
+`
+
+func TestReadLimitRangeSpecs(t *testing.T) {
+	specs, err := readLimitRangeSpecs(strings.NewReader(testSpecFile))
+	assert.NoError(t, err)
+
+	if assert.Len(t, specs, 3) {
+		assert.Equal(t, "team-limits", specs[0].Name)
+		assert.Equal(t, "team-a", specs[0].Namespace)
+		if assert.Len(t, specs[0].Limits, 2) {
+			maxCPU := specs[0].Limits[0].Max[v1.ResourceCPU]
+			assert.Equal(t, "1", (&maxCPU).String())
+			assert.Equal(t, v1.LimitTypePersistentVolumeClaim, specs[0].Limits[1].Type)
+		}
+
+		assert.Equal(t, "batch-limits", specs[1].Name)
+		assert.Equal(t, v1.LimitTypeContainer, specs[1].Limits[0].Type, "type defaults to Container")
+		assert.Equal(t, "pod-limits", specs[2].Name)
+		assert.Equal(t, v1.LimitTypePod, specs[2].Limits[0].Type)
+	}
+}
+
+func TestReadLimitRangeSpecsJSON(t *testing.T) {
+	specs, err := readLimitRangeSpecs(strings.NewReader(`{"name": "json-limits", "limits": [{"type": "Container", "min": {"memory": "64Mi"}}]}`))
+	assert.NoError(t, err)
+
+	if assert.Len(t, specs, 1) {
+		minMemory := specs[0].Limits[0].Min[v1.ResourceMemory]
+		assert.Equal(t, "64Mi", (&minMemory).String())
+	}
+}
+
+func TestReadLimitRangeSpecsRejectsUnknownFields(t *testing.T) {
+	_, err := readLimitRangeSpecs(strings.NewReader("name: typo\nlimits:\n- type: Container\n  maximum:\n    cpu: 1\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown field \"maximum\"")
+	}
+}
+
+func TestReadLimitRangeSpecsInvalidQuantity(t *testing.T) {
+	_, err := readLimitRangeSpecs(strings.NewReader("name: bad\nlimits:\n- max:\n    cpu: lots\n"))
+	assert.Error(t, err)
+}
+
+func TestCompleteWithSpecFromStdin(t *testing.T) {
+	options := &LimitOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		filename:    "-",
+		IOStreams: genericclioptions.IOStreams{
+			In: strings.NewReader(testSpecFile),
+		},
+	}
+	*options.configFlags.Namespace = "flag-namespace"
+
+	err := options.Complete(&cobra.Command{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, options.specs, 3)
+	assert.True(t, options.explicitNamespace)
+}
+
+func TestCompleteWithSpecFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "limits.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(testSpecFile), 0o600))
+
+	options := &LimitOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		namespace:   "default",
+		filename:    filename,
+	}
+
+	err := options.Complete(&cobra.Command{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, options.specs, 3)
+
+	options.filename = filepath.Join(t.TempDir(), "missing.yaml")
+	err = options.Complete(&cobra.Command{}, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to open spec file")
+	}
+}
+
+func TestLimitRangeObjectsFromSpecs(t *testing.T) {
+	specs, err := readLimitRangeSpecs(strings.NewReader(testSpecFile))
+	assert.NoError(t, err)
+
+	options := &LimitOptions{
+		namespace: "default",
+		specs:     specs,
+		maxCPU:    "2",
+		maxPodCPU: "8",
+	}
+
+	limitRanges := options.limitRangeObjects()
+
+	if assert.Len(t, limitRanges, 3) {
+		teamLimits := limitRanges[0]
+		assert.Equal(t, "team-a", teamLimits.Namespace, "spec namespace wins over the context namespace")
+		if assert.Len(t, teamLimits.Spec.Limits, 3) {
+			maxCPU := teamLimits.Spec.Limits[0].Max[v1.ResourceCPU]
+			maxMemory := teamLimits.Spec.Limits[0].Max[v1.ResourceMemory]
+			assert.Equal(t, "2", (&maxCPU).String(), "flags override file values")
+			assert.Equal(t, "1Gi", (&maxMemory).String())
+			assert.Equal(t, v1.LimitTypePersistentVolumeClaim, teamLimits.Spec.Limits[1].Type)
+			assert.Equal(t, v1.LimitTypePod, teamLimits.Spec.Limits[2].Type, "flags add missing items")
+		}
+
+		assert.Equal(t, "default", limitRanges[1].Namespace)
+		assert.Len(t, limitRanges[2].Spec.Limits, 2)
+	}
+
+	// The spec must not be modified by the flag overrides
+	specMaxCPU := specs[0].Limits[0].Max[v1.ResourceCPU]
+	assert.Equal(t, "1", (&specMaxCPU).String())
+
+	options.explicitNamespace = true
+	assert.Equal(t, "default", options.limitRangeObjects()[0].Namespace, "--namespace wins over the spec namespace")
+}
+
+func TestValidateWithSpecs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          string
+		options       *LimitOptions
+		errorContains string
+	}{
+		{
+			name:    "Valid spec",
+			spec:    testSpecFile,
+			options: &LimitOptions{namespace: "default"},
+		},
+		{
+			name:    "Name override for a single spec",
+			spec:    "limits:\n- max:\n    cpu: 1\n",
+			options: &LimitOptions{namespace: "default", name: "from-args"},
+		},
+		{
+			name:          "Name with several specs",
+			spec:          testSpecFile,
+			options:       &LimitOptions{namespace: "default", name: "from-args"},
+			errorContains: "name cannot be given when the file describes 3 LimitRanges",
+		},
+		{
+			name:          "Missing name",
+			spec:          "limits:\n- max:\n    cpu: 1\n",
+			options:       &LimitOptions{namespace: "default"},
+			errorContains: "name is required",
+		},
+		{
+			name:          "No limits",
+			spec:          "name: empty\n",
+			options:       &LimitOptions{namespace: "default"},
+			errorContains: "limitrange \"empty\": at least one resource limit or request must be specified",
+		},
+		{
+			name:          "Zero quantity in file",
+			spec:          "name: zero\nlimits:\n- type: Pod\n  max:\n    memory: 0\n",
+			options:       &LimitOptions{namespace: "default"},
+			errorContains: "limitrange \"zero\": invalid limits[0].max.memory value: must be greater than zero",
+		},
+		{
+			name:          "Zero extended resource in file",
+			spec:          "name: zero\nlimits:\n- type: Pod\n  max:\n    example.com/foo: 0\n",
+			options:       &LimitOptions{namespace: "default"},
+			errorContains: "limitrange \"zero\": invalid limits[0].max[example.com/foo] value: must be greater than zero",
+		},
+		{
+			name:          "Pod defaults in file",
+			spec:          "name: pod\nlimits:\n- type: Pod\n  default:\n    cpu: 1\n",
+			options:       &LimitOptions{namespace: "default"},
+			errorContains: "default may not be specified for Pod limits",
+		},
+		{
+			name:          "Unknown type in file",
+			spec:          "name: node\nlimits:\n- type: Node\n  max:\n    cpu: 1\n",
+			options:       &LimitOptions{namespace: "default"},
+			errorContains: "unsupported limit type \"Node\"",
+		},
+		{
+			name:          "Flag override breaks ordering",
+			spec:          "name: ordering\nlimits:\n- min:\n    cpu: 500m\n",
+			options:       &LimitOptions{namespace: "default", maxCPU: "100m"},
+			errorContains: "limits[0].min.cpu (500m) must not be greater than --max-cpu=100m",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			specs, err := readLimitRangeSpecs(strings.NewReader(tc.spec))
+			assert.NoError(t, err)
+			tc.options.specs = specs
+
+			err = tc.options.Validate()
+			if tc.errorContains != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunDryRunClientWithSpecs(t *testing.T) {
+	specs, err := readLimitRangeSpecs(strings.NewReader(testSpecFile))
+	assert.NoError(t, err)
+
+	options := &LimitOptions{
+		namespace: "default",
+		specs:     specs,
+		dryRun:    "client",
+		output:    "yaml",
+		IOStreams: genericclioptions.IOStreams{
+			Out: new(bytes.Buffer),
+		},
+	}
+
+	err = options.Run()
+	assert.NoError(t, err)
+
+	output := options.IOStreams.Out.(*bytes.Buffer).String()
+	assert.Equal(t, 2, strings.Count(output, "---\n"))
+	assert.Contains(t, output, "name: team-limits")
+	assert.Contains(t, output, "name: batch-limits")
+	assert.Contains(t, output, "name: pod-limits")
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// limitTableHeader is the header of the tables listing the values of LimitRange items
+var limitTableHeader = []string{"TYPE", "RESOURCE", "MIN", "MAX", "DEFAULT-REQUEST", "DEFAULT", "RATIO"}
+
+// limitItemRows returns one row per resource of item, in the column order of limitTableHeader
+func limitItemRows(item v1.LimitRangeItem) [][]string {
+	var rows [][]string
+	for _, name := range itemResourceNames(item) {
+		rows = append(rows, []string{
+			string(item.Type),
+			string(name),
+			quantityCell(item.Min, name),
+			quantityCell(item.Max, name),
+			quantityCell(item.DefaultRequest, name),
+			quantityCell(item.Default, name),
+			quantityCell(item.MaxLimitRequestRatio, name),
+		})
+	}
+	return rows
+}
+
+// quantityCell formats the quantity of name in list, or "-" when it is not set
+func quantityCell(list v1.ResourceList, name v1.ResourceName) string {
+	quantity, ok := list[name]
+	if !ok {
+		return "-"
+	}
+	return quantity.String()
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// standardContainerResources are the resources that Container and Pod items may name without
+// a domain prefix, besides hugepages-<size>
+var standardContainerResources = map[v1.ResourceName]bool{
+	v1.ResourceCPU:              true,
+	v1.ResourceMemory:           true,
+	v1.ResourceEphemeralStorage: true,
+}
+
+// valueNamer returns how error messages refer to the value of field for resource name of an
+// item, and whether that is the name of the flag that set the value
+type valueNamer func(field limitField, name v1.ResourceName) (string, bool)
+
+// limitBound is a single value of a LimitRangeItem together with where it came from
+type limitBound struct {
+	// source is the flag that set the value when isFlag is true, otherwise the field holding it
+	source   string
+	isFlag   bool
+	quantity resource.Quantity
+	// derived names the field the API server fills from source when it is left unset
+	derived string
+}
+
+// String describes the bound in terms of the flag or field it came from
+func (b limitBound) String() string {
+	value := fmt.Sprintf("%s (%s)", b.source, b.quantity.String())
+	if b.isFlag {
+		value = fmt.Sprintf("--%s=%s", b.source, b.quantity.String())
+	}
+	if b.derived != "" {
+		return fmt.Sprintf("the %s taken from %s", b.derived, value)
+	}
+	return value
+}
+
+// validateQuantities checks that every value of item is positive and that ratios are at least 1
+func validateQuantities(item v1.LimitRangeItem, nameOf valueNamer) error {
+	for _, name := range itemResourceNames(item) {
+		for _, field := range []limitField{limitFieldMax, limitFieldMin, limitFieldDefault, limitFieldDefaultRequest, limitFieldRatio} {
+			quantity, ok := resourceListFor(&item, field)[name]
+			if !ok {
+				continue
+			}
+			source, _ := nameOf(field, name)
+			if quantity.Sign() != 1 {
+				// Sign() returns -1 for negative, 0 for zero, 1 for positive
+				return fmt.Errorf("invalid %s value: must be greater than zero", source)
+			}
+			if field == limitFieldRatio && quantity.Cmp(resource.MustParse("1")) < 0 {
+				// A limit can never be smaller than its request
+				return fmt.Errorf("invalid %s value: must be at least 1", source)
+			}
+		}
+	}
+	return nil
+}
+
+// validateLimitRangeItem checks the rules the API server enforces for each item type
+func validateLimitRangeItem(item v1.LimitRangeItem) error {
+	if !isKnownLimitType(item.Type) {
+		return fmt.Errorf("unsupported limit type %q, must be one of Container, Pod or PersistentVolumeClaim", item.Type)
+	}
+	if item.Type == v1.LimitTypePod {
+		// Defaults are applied per container, so the API server refuses them for Pods
+		if len(item.Default) > 0 {
+			return fmt.Errorf("default may not be specified for %s limits", item.Type)
+		}
+		if len(item.DefaultRequest) > 0 {
+			return fmt.Errorf("defaultRequest may not be specified for %s limits", item.Type)
+		}
+	}
+	for _, name := range itemResourceNames(item) {
+		if err := validateResourceName(item.Type, name); err != nil {
+			return err
+		}
+	}
+	if item.Type == v1.LimitTypePersistentVolumeClaim {
+		_, hasMin := item.Min[v1.ResourceStorage]
+		_, hasMax := item.Max[v1.ResourceStorage]
+		if !hasMin && !hasMax {
+			return fmt.Errorf("either minimum or maximum storage must be specified for %s limits", item.Type)
+		}
+	}
+	return nil
+}
+
+// validateLimitOrdering checks that min <= defaultRequest <= default <= max holds for every
+// resource of item and that the limit to request ratio agrees with those values.
+// Container defaults are resolved the way the API server does before it validates them:
+// an unset default falls back to max, and an unset defaultRequest to default or min.
+func validateLimitOrdering(item v1.LimitRangeItem, nameOf valueNamer) error {
+	for _, name := range itemResourceNames(item) {
+		bound := func(field limitField) (limitBound, bool) {
+			q, ok := resourceListFor(&item, field)[name]
+			source, isFlag := nameOf(field, name)
+			return limitBound{source: source, isFlag: isFlag, quantity: q}, ok
+		}
+
+		minBound, hasMin := bound(limitFieldMin)
+		maxBound, hasMax := bound(limitFieldMax)
+		defaultBound, hasDefault := bound(limitFieldDefault)
+		requestBound, hasRequest := bound(limitFieldDefaultRequest)
+		ratioBound, hasRatio := bound(limitFieldRatio)
+		explicitDefaults := hasDefault || hasRequest
+
+		if item.Type == v1.LimitTypeContainer {
+			if !hasDefault && hasMax {
+				defaultBound, hasDefault = maxBound, true
+				defaultBound.derived = "default limit"
+			}
+			if !hasRequest && hasDefault {
+				requestBound, hasRequest = defaultBound, true
+				requestBound.derived = "default request"
+			} else if !hasRequest && hasMin {
+				requestBound, hasRequest = minBound, true
+				requestBound.derived = "default request"
+			}
+		}
+
+		orderings := []struct {
+			low, high limitBound
+			ok        bool
+		}{
+			{minBound, maxBound, hasMin && hasMax},
+			{minBound, requestBound, hasMin && hasRequest},
+			{requestBound, maxBound, hasRequest && hasMax},
+			{minBound, defaultBound, hasMin && hasDefault},
+			{defaultBound, maxBound, hasDefault && hasMax},
+			{requestBound, defaultBound, hasRequest && hasDefault},
+		}
+		for _, ordering := range orderings {
+			if ordering.ok && ordering.low.quantity.Cmp(ordering.high.quantity) > 0 {
+				return fmt.Errorf("%s must not be greater than %s", ordering.low, ordering.high)
+			}
+		}
+		// Resources that cannot be overcommitted, such as extended resources and hugepages,
+		// must be requested in full
+		if hasDefault && hasRequest && !isOvercommitAllowed(name) && requestBound.quantity.Cmp(defaultBound.quantity) != 0 {
+			return fmt.Errorf("%s must equal %s: %s cannot be overcommitted", requestBound, defaultBound, name)
+		}
+
+		if !hasRatio {
+			continue
+		}
+		ratio := ratioBound.quantity.AsApproximateFloat64()
+		if hasMin && hasMax {
+			maxOverMin := maxBound.quantity.AsApproximateFloat64() / minBound.quantity.AsApproximateFloat64()
+			if ratio > maxOverMin {
+				return fmt.Errorf("%s must not be greater than %s divided by %s (%g)", ratioBound, maxBound, minBound, maxOverMin)
+			}
+		}
+		// Only hold defaults derived by the server to the ratio when some default was given,
+		// otherwise a plain min/max/ratio policy that forces explicit resources would be refused
+		if hasDefault && hasRequest && explicitDefaults {
+			defaultOverRequest := defaultBound.quantity.AsApproximateFloat64() / requestBound.quantity.AsApproximateFloat64()
+			if defaultOverRequest > ratio {
+				return fmt.Errorf("%s divided by %s (%g) must not be greater than %s", defaultBound, requestBound, defaultOverRequest, ratioBound)
+			}
+		}
+	}
+	return nil
+}
+
+// validateResourceName checks name with the rules the API server applies to the resources of
+// limitType items: unprefixed names must be standard resources of the item type, and prefixed
+// names outside kubernetes.io must be valid extended resource names
+func validateResourceName(limitType v1.LimitType, name v1.ResourceName) error {
+	if errs := validation.IsQualifiedName(string(name)); len(errs) > 0 {
+		return fmt.Errorf("invalid resource name %q: %s", name, strings.Join(errs, "; "))
+	}
+	if !strings.Contains(string(name), "/") {
+		if limitType == v1.LimitTypePersistentVolumeClaim {
+			if name != v1.ResourceStorage {
+				return fmt.Errorf("invalid resource name %q: must be storage or a prefixed name such as example.com/%s", name, name)
+			}
+			return nil
+		}
+		if !standardContainerResources[name] && !strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix) {
+			return fmt.Errorf("invalid resource name %q: must be cpu, memory, ephemeral-storage, hugepages-<size> or a prefixed name such as example.com/%s", name, name)
+		}
+		return nil
+	}
+	if !isNativeResource(name) {
+		requestName := v1.DefaultResourceRequestsPrefix + string(name)
+		if strings.HasPrefix(string(name), v1.DefaultResourceRequestsPrefix) || len(validation.IsQualifiedName(requestName)) > 0 {
+			return fmt.Errorf("invalid resource name %q: does not follow the extended resource name standard", name)
+		}
+	}
+	return nil
+}
+
+// isNativeResource reports whether name is unprefixed or in the kubernetes.io domain
+func isNativeResource(name v1.ResourceName) bool {
+	return !strings.Contains(string(name), "/") || strings.Contains(string(name), v1.ResourceDefaultNamespacePrefix)
+}
+
+// isOvercommitAllowed reports whether requests of name may be lower than its limits, which the
+// API server only allows for native resources other than hugepages
+func isOvercommitAllowed(name v1.ResourceName) bool {
+	return isNativeResource(name) && !strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix)
+}
+
+// isKnownLimitType reports whether limitType is one of the supported LimitRangeItem types
+func isKnownLimitType(limitType v1.LimitType) bool {
+	for _, known := range limitTypes {
+		if limitType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// storedLimitRanges returns the LimitRanges to create as the API server would store them, with
+// the server defaults filled in. This is what pods are admitted against.
+func (o *LimitOptions) storedLimitRanges() []*v1.LimitRange {
+	limitRanges := o.limitRangeObjects()
+	for _, limitRange := range limitRanges {
+		for i := range limitRange.Spec.Limits {
+			applyServerDefaults(&limitRange.Spec.Limits[i])
+		}
+	}
+	return limitRanges
+}
+
+// applyServerDefaults fills in the container defaults the API server derives when a
+// LimitRange is stored: an unset default falls back to max, and an unset defaultRequest
+// to default or min
+func applyServerDefaults(item *v1.LimitRangeItem) {
+	if item.Type != v1.LimitTypeContainer {
+		return
+	}
+	if item.Default == nil {
+		item.Default = v1.ResourceList{}
+	}
+	if item.DefaultRequest == nil {
+		item.DefaultRequest = v1.ResourceList{}
+	}
+	for name, quantity := range item.Max {
+		if _, ok := item.Default[name]; !ok {
+			item.Default[name] = quantity.DeepCopy()
+		}
+	}
+	for name, quantity := range item.Default {
+		if _, ok := item.DefaultRequest[name]; !ok {
+			item.DefaultRequest[name] = quantity.DeepCopy()
+		}
+	}
+	for name, quantity := range item.Min {
+		if _, ok := item.DefaultRequest[name]; !ok {
+			item.DefaultRequest[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// itemResourceNames returns the sorted names of all resources referenced by item
+func itemResourceNames(item v1.LimitRangeItem) []v1.ResourceName {
+	seen := map[v1.ResourceName]bool{}
+	var names []v1.ResourceName
+	for _, list := range []v1.ResourceList{item.Min, item.Max, item.Default, item.DefaultRequest, item.MaxLimitRequestRatio} {
+		for name := range list {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
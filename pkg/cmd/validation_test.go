@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestValidateLimitRangeItem(t *testing.T) {
+	podItem := v1.LimitRangeItem{
+		Type: v1.LimitTypePod,
+		Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+	}
+	assert.NoError(t, validateLimitRangeItem(podItem))
+
+	podItem.Default = v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+	err := validateLimitRangeItem(podItem)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "default may not be specified for Pod limits")
+	}
+
+	podItem.Default = nil
+	podItem.DefaultRequest = v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+	err = validateLimitRangeItem(podItem)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "defaultRequest may not be specified for Pod limits")
+	}
+
+	containerItem := v1.LimitRangeItem{
+		Type:    v1.LimitTypeContainer,
+		Default: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+	}
+	assert.NoError(t, validateLimitRangeItem(containerItem))
+
+	containerItem.Max = v1.ResourceList{"foo": resource.MustParse("1")}
+	err = validateLimitRangeItem(containerItem)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `invalid resource name "foo": must be cpu, memory, ephemeral-storage, hugepages-<size> or a prefixed name`)
+	}
+
+	pvcItem := v1.LimitRangeItem{
+		Type: v1.LimitTypePersistentVolumeClaim,
+		Max:  v1.ResourceList{"example.com/foo": resource.MustParse("1")},
+	}
+	err = validateLimitRangeItem(pvcItem)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "either minimum or maximum storage must be specified")
+	}
+
+	pvcItem.Max = v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi"), v1.ResourceCPU: resource.MustParse("1")}
+	err = validateLimitRangeItem(pvcItem)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `invalid resource name "cpu": must be storage or a prefixed name`)
+	}
+}
+
+func TestValidateLimitOrdering(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       *LimitOptions
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Consistent container values",
+			options: &LimitOptions{
+				minCPU:            "100m",
+				defaultRequestCPU: "200m",
+				defaultCPU:        "500m",
+				maxCPU:            "1",
+				ratioCPU:          "4",
+			},
+			expectError: false,
+		},
+		{
+			name: "Min above max",
+			options: &LimitOptions{
+				minCPU: "2",
+				maxCPU: "1",
+			},
+			expectError:   true,
+			errorContains: "--min-cpu=2 must not be greater than --max-cpu=1",
+		},
+		{
+			name: "Default above max",
+			options: &LimitOptions{
+				defaultMemory: "1Gi",
+				maxMemory:     "512Mi",
+			},
+			expectError:   true,
+			errorContains: "--default-memory=1Gi must not be greater than --max-memory=512Mi",
+		},
+		{
+			name: "Default request above default",
+			options: &LimitOptions{
+				defaultRequestCPU: "1",
+				defaultCPU:        "500m",
+			},
+			expectError:   true,
+			errorContains: "--default-request-cpu=1 must not be greater than --default-cpu=500m",
+		},
+		{
+			name: "Default request below min",
+			options: &LimitOptions{
+				minMemory:            "256Mi",
+				defaultRequestMemory: "128Mi",
+			},
+			expectError:   true,
+			errorContains: "--min-memory=256Mi must not be greater than --default-request-memory=128Mi",
+		},
+		{
+			name: "Default request above max used as default limit",
+			options: &LimitOptions{
+				defaultRequestCPU: "2",
+				maxCPU:            "1",
+			},
+			expectError:   true,
+			errorContains: "--default-request-cpu=2 must not be greater than --max-cpu=1",
+		},
+		{
+			name: "Default limit below min used as default request",
+			options: &LimitOptions{
+				minCPU:     "2",
+				defaultCPU: "1",
+			},
+			expectError:   true,
+			errorContains: "--min-cpu=2 must not be greater than the default request taken from --default-cpu=1",
+		},
+		{
+			name: "Pod min above max",
+			options: &LimitOptions{
+				minPodMemory: "2Gi",
+				maxPodMemory: "1Gi",
+			},
+			expectError:   true,
+			errorContains: "--min-pod-memory=2Gi must not be greater than --max-pod-memory=1Gi",
+		},
+		{
+			name: "Ratio above max over min",
+			options: &LimitOptions{
+				minCPU:   "500m",
+				maxCPU:   "1",
+				ratioCPU: "4",
+			},
+			expectError:   true,
+			errorContains: "--ratio-cpu=4 must not be greater than --max-cpu=1 divided by --min-cpu=500m (2)",
+		},
+		{
+			name: "Defaults exceed ratio",
+			options: &LimitOptions{
+				defaultCPU:        "2",
+				defaultRequestCPU: "100m",
+				ratioCPU:          "4",
+			},
+			expectError:   true,
+			errorContains: "--default-cpu=2 divided by --default-request-cpu=100m (20) must not be greater than --ratio-cpu=4",
+		},
+		{
+			name: "Ratio with only derived defaults",
+			options: &LimitOptions{
+				minCPU:   "100m",
+				maxCPU:   "1",
+				ratioCPU: "4",
+			},
+			expectError: false,
+		},
+		{
+			name: "Extended resource default request below default",
+			options: &LimitOptions{
+				defaultResources:        []string{"example.com/foo=2"},
+				defaultRequestResources: []string{"example.com/foo=1"},
+			},
+			expectError:   true,
+			errorContains: "--default-request=example.com/foo=1 must equal --default=example.com/foo=2: example.com/foo cannot be overcommitted",
+		},
+		{
+			name: "Hugepages default request below max used as default limit",
+			options: &LimitOptions{
+				maxResources:            []string{"hugepages-2Mi=1Gi"},
+				defaultRequestResources: []string{"hugepages-2Mi=512Mi"},
+			},
+			expectError:   true,
+			errorContains: "--default-request=hugepages-2Mi=512Mi must equal the default limit taken from --max=hugepages-2Mi=1Gi",
+		},
+		{
+			name: "Extended resource with derived defaults",
+			options: &LimitOptions{
+				minResources: []string{"example.com/foo=1"},
+				maxResources: []string{"example.com/foo=2"},
+			},
+			expectError: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			limitRange := tc.options.createLimitRangeObject()
+			for i, item := range limitRange.Spec.Limits {
+				if err = validateLimitOrdering(item, tc.options.valueNamer(limitRange, i)); err != nil {
+					break
+				}
+			}
+			if tc.expectError {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyServerDefaults(t *testing.T) {
+	item := v1.LimitRangeItem{
+		Type: v1.LimitTypeContainer,
+		Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("1Gi")},
+		Min:  v1.ResourceList{v1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+		Default: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+
+	applyServerDefaults(&item)
+
+	defaultCPU := item.Default[v1.ResourceCPU]
+	defaultMemory := item.Default[v1.ResourceMemory]
+	requestCPU := item.DefaultRequest[v1.ResourceCPU]
+	requestMemory := item.DefaultRequest[v1.ResourceMemory]
+	requestStorage := item.DefaultRequest[v1.ResourceEphemeralStorage]
+	assert.Equal(t, "1", (&defaultCPU).String(), "default falls back to max")
+	assert.Equal(t, "512Mi", (&defaultMemory).String(), "explicit default is kept")
+	assert.Equal(t, "1", (&requestCPU).String(), "default request falls back to default")
+	assert.Equal(t, "512Mi", (&requestMemory).String())
+	assert.Equal(t, "1Gi", (&requestStorage).String(), "default request falls back to min")
+
+	podItem := v1.LimitRangeItem{
+		Type: v1.LimitTypePod,
+		Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+	}
+	applyServerDefaults(&podItem)
+	assert.Empty(t, podItem.Default)
+	assert.Empty(t, podItem.DefaultRequest)
+}
+
+func TestStoredLimitRanges(t *testing.T) {
+	options := &LimitOptions{name: "proposed", namespace: "default", maxCPU: "1", minCPU: "100m"}
+
+	limitRanges := options.storedLimitRanges()
+	if assert.Len(t, limitRanges, 1) && assert.Len(t, limitRanges[0].Spec.Limits, 1) {
+		item := limitRanges[0].Spec.Limits[0]
+		defaultCPU := item.Default[v1.ResourceCPU]
+		requestCPU := item.DefaultRequest[v1.ResourceCPU]
+		assert.Equal(t, "1", (&defaultCPU).String(), "default limit falls back to max")
+		assert.Equal(t, "1", (&requestCPU).String(), "default request falls back to the default limit")
+	}
+}